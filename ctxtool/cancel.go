@@ -19,6 +19,8 @@ package ctxtool
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"time"
 )
 
@@ -36,7 +38,8 @@ type cancelContext struct {
 //   ctx := ac.With(context.WithTimeout(ctx, 5 * time.Second))
 //   ... // do something with ctx
 type AutoCancel struct {
-	funcs []context.CancelFunc
+	mu    sync.Mutex
+	funcs []func() error
 }
 
 // CancelContext holds a context with its corresponding CancelFunc.
@@ -52,19 +55,86 @@ type CancelContext struct {
 	cancel context.CancelFunc
 }
 
-// Cancel calls all registered cancel functions in reverse order.
+// Cancel calls all registered cancel functions in reverse order. Errors
+// returned by functions registered with AddErr are ignored.
 func (ac *AutoCancel) Cancel() {
-	for _, fn := range ac.funcs {
-		defer fn()
+	_ = ac.CancelErr()
+}
+
+// CancelErr calls all registered cancel functions in reverse order, like
+// Cancel. All functions are run, even if one of them fails. The errors
+// returned by functions registered with AddErr are combined using
+// errors.Join, in the order the functions have been run. Each registered
+// function is run only once, later calls to Cancel or CancelErr only run
+// functions that have been added in the meantime.
+func (ac *AutoCancel) CancelErr() (err error) {
+	ac.mu.Lock()
+	funcs := ac.funcs
+	ac.funcs = nil
+	ac.mu.Unlock()
+
+	var errs []error
+	defer func() {
+		err = errors.Join(errs...)
+	}()
+
+	for _, fn := range funcs {
+		fn := fn
+		defer func() {
+			if err := fn(); err != nil {
+				errs = append(errs, err)
+			}
+		}()
 	}
+	return nil
 }
 
 // Add adds a new cancel function to the AutoCancel. The function will be run
 // before any other already registered cancel function.
 func (ac *AutoCancel) Add(fn context.CancelFunc) {
+	ac.AddErr(func() error {
+		fn()
+		return nil
+	})
+}
+
+// AddErr adds a new cleanup function that can fail to the AutoCancel, e.g.
+// for closing files or flushing writers. The function will be run before any
+// other already registered cancel function. Errors are reported by
+// CancelErr.
+func (ac *AutoCancel) AddErr(fn func() error) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
 	ac.funcs = append(ac.funcs, fn)
 }
 
+// Bind ties the AutoCancel to the lifetime of ctx. Cancel is run
+// automatically once ctx is cancelled. Bind does not block, but starts a
+// go-routine waiting for ctx. The go-routine is stopped if Cancel is called
+// before ctx is cancelled.
+//
+// Example:
+//
+//	var ac AutoCancel
+//	ac.Bind(requestCtx)
+//	ctx := ac.With(context.WithCancel(context.Background()))
+//	... // ctx is cancelled once the request is done
+func (ac *AutoCancel) Bind(ctx canceller) {
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+	ac.Add(func() {
+		stopOnce.Do(func() { close(stop) })
+	})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			ac.Cancel()
+		case <-stop:
+		}
+	}()
+}
+
 // With is used to wrap a Context constructer call that returns a context and a
 // cancel function.  The cancel function is automatically added to AutoCancel
 // and the original context is returned as is.
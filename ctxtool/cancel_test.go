@@ -19,9 +19,12 @@ package ctxtool
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
 )
 
 func TestAutoCancel(t *testing.T) {
@@ -45,6 +48,102 @@ func TestAutoCancel(t *testing.T) {
 		ac.Cancel()
 		assert.Error(t, ctx.Err())
 	})
+
+	t.Run("bind cancels on parent cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		parent, cancelParent := context.WithCancel(context.Background())
+		defer cancelParent()
+
+		var ac AutoCancel
+		ac.Bind(parent)
+		ctx := ac.With(context.WithCancel(context.Background()))
+
+		cancelParent()
+		<-ctx.Done()
+		assert.Error(t, ctx.Err())
+	})
+
+	t.Run("bind stops watcher on manual cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var ac AutoCancel
+		ac.Bind(context.Background())
+		ctx := ac.With(context.WithCancel(context.Background()))
+
+		ac.Cancel()
+		assert.Error(t, ctx.Err())
+	})
+
+	t.Run("manual cancel after bound cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		parent, cancelParent := context.WithCancel(context.Background())
+		var closeCount, cancelCount int32
+		closed := make(chan struct{}, 2)
+
+		var ac AutoCancel
+		ac.Bind(parent)
+		ac.AddErr(func() error {
+			atomic.AddInt32(&closeCount, 1)
+			closed <- struct{}{}
+			return nil
+		})
+		ac.Add(func() { atomic.AddInt32(&cancelCount, 1) })
+		ctx := ac.With(context.WithCancel(context.Background()))
+
+		cancelParent()
+		<-ctx.Done()
+		<-closed
+		ac.Cancel()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&closeCount))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&cancelCount))
+	})
+
+	t.Run("cancel err aggregates errors in reverse order", func(t *testing.T) {
+		err1 := errors.New("err1")
+		err3 := errors.New("err3")
+
+		var values []int
+		var ac AutoCancel
+		ac.AddErr(func() error { values = append(values, 1); return err1 })
+		ac.Add(func() { values = append(values, 2) })
+		ac.AddErr(func() error { values = append(values, 3); return err3 })
+
+		err := ac.CancelErr()
+		assert.Equal(t, []int{3, 2, 1}, values)
+		assert.True(t, errors.Is(err, err1))
+		assert.True(t, errors.Is(err, err3))
+		assert.Equal(t, "err3\nerr1", err.Error())
+	})
+
+	t.Run("cancel err returns nil without failures", func(t *testing.T) {
+		var ac AutoCancel
+		ac.Add(func() {})
+		ac.AddErr(func() error { return nil })
+		assert.NoError(t, ac.CancelErr())
+	})
+
+	t.Run("cancel runs err functions", func(t *testing.T) {
+		count := 0
+		var ac AutoCancel
+		ac.AddErr(func() error { count++; return errors.New("oops") })
+		ac.Cancel()
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("functions run only once", func(t *testing.T) {
+		var count1, count2 int
+		var ac AutoCancel
+		ac.AddErr(func() error { count1++; return nil })
+		ac.Add(func() { count2++ })
+
+		assert.NoError(t, ac.CancelErr())
+		assert.NoError(t, ac.CancelErr())
+		assert.Equal(t, 1, count1)
+		assert.Equal(t, 1, count2)
+	})
 }
 
 func TestCancelContext(t *testing.T) {
@@ -24,7 +24,10 @@ import (
 
 type chanCanceller <-chan struct{}
 
-type chanContext <-chan struct{}
+type chanContext struct {
+	ch  <-chan struct{}
+	err error
+}
 
 // WithChannel creates a context that is cancelled if the parent context is cancelled
 // or if the given channel is closed.
@@ -32,9 +35,43 @@ func WithChannel(parent canceller, ch <-chan struct{}) (context.Context, context
 	return MergeCancellation(parent, chanCanceller(ch))
 }
 
-// FromChannel creates a new context from a channel.
+// FromChannel creates a new context from a channel. The context reports
+// context.Canceled once the channel is closed.
 func FromChannel(ch <-chan struct{}) context.Context {
-	return chanContext(ch)
+	return FromChannelErr(ch, context.Canceled)
+}
+
+// FromChannelErr creates a new context from a channel. The context reports err
+// once the channel is closed. This allows channels signaling a timeout to
+// report context.DeadlineExceeded for example.
+func FromChannelErr(ch <-chan struct{}, err error) context.Context {
+	return chanContext{ch: ch, err: err}
+}
+
+// Drain receives values from ch and passes them to handle until ch is closed
+// or ctx is cancelled. Drain returns nil if ch has been closed, and ctx.Err()
+// if ctx has been cancelled. Once ctx is cancelled, handle will not be called
+// anymore, even if ch still has values buffered.
+//
+// A nil channel is never closed. Drain blocks until ctx is cancelled in that case.
+func Drain[T any](ctx canceller, ch <-chan T, handle func(T)) error {
+	done := ctx.Done()
+	for {
+		select {
+		case <-done:
+			return ctx.Err()
+		case v, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			// select picks a random case if both are ready. Check for
+			// cancellation again, so handle is never called after cancel.
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			handle(v)
+		}
+	}
 }
 
 func (c chanCanceller) Done() <-chan struct{} {
@@ -55,13 +92,13 @@ func (c chanContext) Deadline() (deadline time.Time, ok bool) {
 }
 
 func (c chanContext) Done() <-chan struct{} {
-	return c
+	return c.ch
 }
 
 func (c chanContext) Err() error {
 	select {
-	case <-c:
-		return context.Canceled
+	case <-c.ch:
+		return c.err
 	default:
 		return nil
 	}
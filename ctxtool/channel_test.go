@@ -20,6 +20,7 @@ package ctxtool
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/goleak"
@@ -71,3 +72,90 @@ func TestWithChannel(t *testing.T) {
 		assert.Equal(t, "world", ctx.Value("hello"))
 	})
 }
+
+func TestFromChannel(t *testing.T) {
+	t.Run("not cancelled while channel is open", func(t *testing.T) {
+		ch := make(chan struct{})
+		ctx := FromChannel(ch)
+		assert.NoError(t, ctx.Err())
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+	})
+
+	t.Run("reports canceled after channel is closed", func(t *testing.T) {
+		ch := make(chan struct{})
+		ctx := FromChannel(ch)
+		close(ch)
+		<-ctx.Done()
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+}
+
+func TestFromChannelErr(t *testing.T) {
+	t.Run("not cancelled while channel is open", func(t *testing.T) {
+		ch := make(chan struct{})
+		ctx := FromChannelErr(ch, context.DeadlineExceeded)
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("reports custom error after channel is closed", func(t *testing.T) {
+		ch := make(chan struct{})
+		ctx := FromChannelErr(ch, context.DeadlineExceeded)
+		close(ch)
+		<-ctx.Done()
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	})
+}
+
+func TestDrain(t *testing.T) {
+	t.Run("returns nil on closed channel", func(t *testing.T) {
+		ch := make(chan int, 3)
+		ch <- 1
+		ch <- 2
+		ch <- 3
+		close(ch)
+
+		var values []int
+		err := Drain(context.Background(), ch, func(v int) { values = append(values, v) })
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, values)
+	})
+
+	t.Run("returns context error on cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan int, 2)
+		ch <- 1
+		ch <- 2
+
+		var values []int
+		err := Drain(ctx, ch, func(v int) {
+			values = append(values, v)
+			cancel()
+		})
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, []int{1}, values)
+	})
+
+	t.Run("handle not called after cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		ch := make(chan int, 10)
+		for i := 0; i < 10; i++ {
+			ch <- i
+		}
+
+		err := Drain(ctx, ch, func(v int) { t.Errorf("unexpected call to handle with %v", v) })
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("nil channel blocks until cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := Drain(ctx, (chan int)(nil), func(int) {})
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+}
@@ -0,0 +1,75 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ctxtool
+
+import (
+	"context"
+	"time"
+)
+
+// detachedContext keeps the values of the original context, but is never
+// cancelled and has no deadline.
+type detachedContext struct {
+	parent valuer
+}
+
+// Detach creates a new context that keeps the values of ctx, but is never
+// cancelled and has no deadline. Detach is the inverse of MergeCancellation,
+// and can be used to pass request scoped values to background tasks that must
+// outlive the request.
+func Detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+// WithCleanupTimeout creates a context for running cleanup code after parent
+// has been cancelled. The context keeps the values of parent, but is not
+// cancelled by parent. Instead it is cancelled once timeout has passed, or
+// when the returned CancelFunc is called.
+//
+// WithCleanupTimeout is the recommended way to run post-cancellation cleanup
+// (e.g. outbound calls flushing state) with a bounded time budget.
+//
+// Example:
+//
+//	func (s *service) Run(ctx context.Context) error {
+//		defer func() {
+//			ctx, cancel := ctxtool.WithCleanupTimeout(ctx, 5*time.Second)
+//			defer cancel()
+//			s.flush(ctx)
+//		}()
+//		...
+//	}
+func WithCleanupTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(Detach(parent), timeout)
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
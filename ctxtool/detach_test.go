@@ -0,0 +1,104 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ctxtool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestDetach(t *testing.T) {
+	t.Run("not cancelled by parent", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx := Detach(parent)
+		cancelParent()
+
+		assert.Nil(t, ctx.Done())
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("no deadline", func(t *testing.T) {
+		parent, cancelParent := context.WithTimeout(context.Background(), 1*time.Hour)
+		defer cancelParent()
+
+		_, ok := Detach(parent).Deadline()
+		assert.False(t, ok)
+	})
+
+	t.Run("keeps parent values", func(t *testing.T) {
+		ctx := Detach(contextWithValues("a", 1))
+		assert.Equal(t, 1, ctx.Value("a"))
+		assert.Nil(t, ctx.Value("b"))
+	})
+}
+
+func TestWithCleanupTimeout(t *testing.T) {
+	t.Run("not cancelled by parent", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		parent, cancelParent := context.WithCancel(context.Background())
+		cancelParent()
+
+		ctx, cancel := WithCleanupTimeout(parent, 1*time.Hour)
+		defer cancel()
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("keeps parent values", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		parent, cancelParent := context.WithCancel(contextWithValues("a", 1))
+		cancelParent()
+
+		ctx, cancel := WithCleanupTimeout(parent, 1*time.Hour)
+		defer cancel()
+		assert.Equal(t, 1, ctx.Value("a"))
+	})
+
+	t.Run("uses fresh deadline", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		parent, cancelParent := context.WithTimeout(context.Background(), 1*time.Millisecond)
+		defer cancelParent()
+		<-parent.Done()
+
+		start := time.Now()
+		ctx, cancel := WithCleanupTimeout(parent, 50*time.Millisecond)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.False(t, deadline.Before(start))
+
+		<-ctx.Done()
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	})
+
+	t.Run("cancel func cancels", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := WithCleanupTimeout(context.Background(), 1*time.Hour)
+		cancel()
+		<-ctx.Done()
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+}
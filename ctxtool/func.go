@@ -31,6 +31,25 @@ type funcContext struct {
 
 // WithFunc creates a context that will execute the given function when the
 // parent context gets cancelled.
+//
+// fn runs concurrently with other go-routines observing the parent context.
+// The returned context is marked as done after fn has returned, such that
+// go-routines observing the returned context (and its children) will only
+// react after fn is finished. If the parent context is already cancelled, fn
+// is run asynchronously and the parent context is returned as is. Use
+// WithPreCancelFunc if the returned context must never be done before fn has
+// returned.
+//
+// Example:
+//
+//	ctx, cancel := ctxtool.WithFunc(parent, flush)
+//	defer cancel()
+//	go func() {
+//		<-parent.Done() // might observe cancellation while flush is active
+//	}()
+//	go func() {
+//		<-ctx.Done() // flush has returned
+//	}()
 func WithFunc(parent canceller, fn func()) (context.Context, context.CancelFunc) {
 	ctx := FromCanceller(parent)
 
@@ -40,6 +59,61 @@ func WithFunc(parent canceller, fn func()) (context.Context, context.CancelFunc)
 		return ctx, func() {}
 	}
 
+	return newFuncContext(ctx, fn)
+}
+
+// WithPreCancelFunc creates a context that will execute the given function when
+// the parent context gets cancelled, or the returned CancelFunc is called.
+// Unlike WithFunc, the returned context is guaranteed to not be marked as done
+// before fn has returned, even if the parent context has already been
+// cancelled. Children of the returned context will observe the cancellation
+// only after fn did run. Use WithPreCancelFunc to run cleanup before
+// downstream observers react.
+//
+// Example:
+//
+//	ctx, cancel := ctxtool.WithPreCancelFunc(parent, flush)
+//	defer cancel()
+//	go worker(ctx) // worker observes shutdown after flush has returned
+func WithPreCancelFunc(parent canceller, fn func()) (context.Context, context.CancelFunc) {
+	return newFuncContext(FromCanceller(parent), fn)
+}
+
+// WithCleanup creates a context that is cancelled when the parent context
+// gets cancelled or the returned CancelFunc is called. fn is run exactly once,
+// by whichever happens first. If the parent gets cancelled, fn is run
+// asynchronously. If the CancelFunc is called, fn is run synchronously, such
+// that the CancelFunc only returns after fn has returned. The go-routine
+// watching the parent context returns in both cases.
+//
+// Example:
+//
+//	ctx, cancel := ctxtool.WithCleanup(parent, conn.Close)
+//	defer cancel() // conn is closed when cancel returns
+func WithCleanup(parent canceller, fn func()) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(FromCanceller(parent))
+
+	var cleanupOnce sync.Once
+	cleanup := func() { cleanupOnce.Do(fn) }
+
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+	go func() {
+		select {
+		case <-ctx.Done():
+			cleanup()
+		case <-stop:
+		}
+	}()
+
+	return ctx, func() {
+		stopOnce.Do(func() { close(stop) })
+		cancel()
+		cleanup()
+	}
+}
+
+func newFuncContext(ctx context.Context, fn func()) (context.Context, context.CancelFunc) {
 	chCancel := make(chan struct{})
 	chDone := make(chan struct{})
 	fnCtx := &funcContext{
@@ -58,14 +132,20 @@ func WithFunc(parent canceller, fn func()) (context.Context, context.CancelFunc)
 }
 
 func (ctx *funcContext) wait(cancel <-chan struct{}, done chan struct{}, fn func()) {
-	defer close(done)
+	var err error
+	defer func() {
+		// Publish the error only after fn did return, so to keep Err and Done
+		// consistent.
+		ctx.setErr(err)
+		close(done)
+	}()
 	defer fn()
 
 	select {
 	case <-ctx.Context.Done():
-		ctx.setErr(ctx.Context.Err())
+		err = ctx.Context.Err()
 	case <-cancel:
-		ctx.setErr(context.Canceled)
+		err = context.Canceled
 	}
 }
 
@@ -22,6 +22,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/goleak"
@@ -64,6 +65,21 @@ func TestWithFunc(t *testing.T) {
 		assert.Equal(t, int64(1), count.Load())
 	})
 
+	t.Run("executed only once on repeated cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var count atomic.Int64
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := WithFunc(parent, func() {
+			count.Add(1)
+		})
+		cancel()
+		cancel()
+		cancelParent()
+		<-ctx.Done()
+		assert.Equal(t, int64(1), count.Load())
+	})
+
 	t.Run("wait for other before we continue cancelling", func(t *testing.T) {
 		ctx1, canceler := context.WithCancel(context.Background())
 		defer canceler()
@@ -110,6 +126,155 @@ func TestWithFunc(t *testing.T) {
 	})
 }
 
+func TestWithPreCancelFunc(t *testing.T) {
+	t.Run("executed on cleanup 'cancel' call", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var count atomic.Int64
+		ctx, cancel := WithPreCancelFunc(context.Background(), func() {
+			count.Add(1)
+		})
+		cancel()
+		<-ctx.Done()
+		assert.Equal(t, int64(1), count.Load())
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("done after fn returned on parent cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		parent, cancelParent := context.WithCancel(context.Background())
+		defer cancelParent()
+
+		release := make(chan struct{})
+		var finished atomic.Bool
+		ctx, cancel := WithPreCancelFunc(parent, func() {
+			<-release
+			finished.Store(true)
+		})
+		defer cancel()
+
+		cancelParent()
+		select {
+		case <-ctx.Done():
+			t.Fatal("context done before fn returned")
+		case <-time.After(10 * time.Millisecond):
+		}
+		assert.NoError(t, ctx.Err())
+
+		close(release)
+		<-ctx.Done()
+		assert.True(t, finished.Load())
+	})
+
+	t.Run("done after fn returned if parent is already cancelled", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		parent, cancelParent := context.WithCancel(context.Background())
+		cancelParent()
+
+		release := make(chan struct{})
+		var finished atomic.Bool
+		ctx, cancel := WithPreCancelFunc(parent, func() {
+			<-release
+			finished.Store(true)
+		})
+		defer cancel()
+
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, ctx.Err(), "error reported before fn returned")
+		close(release)
+		<-ctx.Done()
+		assert.True(t, finished.Load())
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+}
+
+func TestWithCleanup(t *testing.T) {
+	t.Run("run synchronously on cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var count atomic.Int64
+		ctx, cancel := WithCleanup(context.Background(), func() {
+			count.Add(1)
+		})
+		cancel()
+		assert.Equal(t, int64(1), count.Load())
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("run on parent cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		done := make(chan struct{})
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := WithCleanup(parent, func() {
+			close(done)
+		})
+		defer cancel()
+
+		cancelParent()
+		<-done
+		<-ctx.Done()
+	})
+
+	t.Run("run if parent is already cancelled", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		done := make(chan struct{})
+		parent, cancelParent := context.WithCancel(context.Background())
+		cancelParent()
+
+		_, cancel := WithCleanup(parent, func() {
+			close(done)
+		})
+		defer cancel()
+		<-done
+	})
+
+	t.Run("run only once on concurrent cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		for i := 0; i < 100; i++ {
+			var count atomic.Int64
+			parent, cancelParent := context.WithCancel(context.Background())
+			_, cancel := WithCleanup(parent, func() {
+				count.Add(1)
+			})
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				cancelParent()
+			}()
+			go func() {
+				defer wg.Done()
+				cancel()
+			}()
+			wg.Wait()
+			cancel()
+
+			assert.Equal(t, int64(1), count.Load())
+		}
+	})
+
+	t.Run("cancel after parent cancel does not run again", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var count atomic.Int64
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := WithCleanup(parent, func() {
+			count.Add(1)
+		})
+
+		cancelParent()
+		<-ctx.Done()
+		cancel()
+		assert.Equal(t, int64(1), count.Load())
+	})
+}
+
 func makeWaitGroup(i int) *sync.WaitGroup {
 	var wg sync.WaitGroup
 	wg.Add(i)
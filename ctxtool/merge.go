@@ -54,6 +54,16 @@ type mergeValueCtx struct {
 	overwrites valuer
 }
 
+type mergeValueNCtx struct {
+	context.Context
+	others []valuer
+}
+
+type valuesCtx struct {
+	context.Context
+	kv []interface{}
+}
+
 // MergeContexts merges cancellation and values of 2 contexts.
 // The resulting context is canceled by the first context that got canceled.
 // The ctx2 overwrites values in ctx1 during value lookup.
@@ -61,6 +71,24 @@ func MergeContexts(ctx1, ctx2 context.Context) (context.Context, context.CancelF
 	return MergeCancellation(MergeValues(MergeDeadline(ctx1, ctx2), ctx2), ctx2)
 }
 
+// WithEarliestDeadline merges cancellation, deadlines, and values of 2
+// contexts, like MergeContexts. In addition the earlier of the two deadlines
+// is enforced: the new context is cancelled with context.DeadlineExceeded once
+// the deadline has passed, even if neither input context gets cancelled.
+func WithEarliestDeadline(ctx1, ctx2 context.Context) (context.Context, context.CancelFunc) {
+	merged, cancelMerged := MergeContexts(ctx1, ctx2)
+	deadline, ok := merged.Deadline()
+	if !ok {
+		return merged, cancelMerged
+	}
+
+	ctx, cancelDeadline := context.WithDeadline(merged, deadline)
+	return ctx, func() {
+		cancelDeadline()
+		cancelMerged()
+	}
+}
+
 // MergeCancellation creates a new context that will be cancelled if one of the
 // two input contexts gets canceled. The `Values` and `Deadline` are taken from the first context.
 func MergeCancellation(parent, other canceller) (context.Context, context.CancelFunc) {
@@ -191,3 +219,112 @@ func MergeDeadline(ctx context.Context, deadliner deadliner) context.Context {
 func (ctx mergedDeadlineCtx) Deadline() (time.Time, bool) {
 	return ctx.deadline, true
 }
+
+// MergeContextsN merges cancellation, deadlines, and values of all contexts.
+// The resulting context is canceled by the first context that got canceled,
+// and reports the earliest deadline. Unlike MergeContexts, value lookups
+// are done from left to right, such that the first context has precedence.
+// If no context is passed, context.Background() is returned.
+func MergeContextsN(ctxs ...context.Context) (context.Context, context.CancelFunc) {
+	if len(ctxs) == 0 {
+		return context.Background(), func() {}
+	}
+
+	var base context.Context = MergeValuesN(ctxs...)
+	for _, ctx := range ctxs {
+		base = MergeDeadline(base, ctx)
+	}
+
+	others := make([]context.Context, 0, len(ctxs))
+	others = append(others, base)
+	others = append(others, ctxs[1:]...)
+	return MergeCancellationN(others...)
+}
+
+// MergeCancellationN creates a new context that will be cancelled if any of
+// the input contexts gets canceled. The `Values` and `Deadline` are taken from
+// the first context. If no context is passed, context.Background() is
+// returned.
+//
+// If only one context is passed, or if none of the contexts can ever be
+// cancelled, the first context is returned as is. The returned CancelFunc
+// is a no-op in that case and does not cancel the context, like with
+// MergeCancellation.
+//
+// Only a single go-routine is used to wait for cancellation, independent of
+// the number of contexts.
+func MergeCancellationN(ctxs ...context.Context) (context.Context, context.CancelFunc) {
+	switch len(ctxs) {
+	case 0:
+		return context.Background(), func() {}
+	case 1:
+		return ctxs[0], func() {}
+	case 2:
+		return MergeCancellation(ctxs[0], ctxs[1])
+	}
+
+	for _, ctx := range ctxs {
+		if ctx.Done() != nil {
+			// A merged context with a single go-routine waiting for
+			// cancellation is a quorum of 1.
+			return mergeQuorum(ctxs[0], 1, ctxs)
+		}
+	}
+
+	// context is never cancelled.
+	return ctxs[0], func() {}
+}
+
+// MergeValuesN merges the values of all contexts. Value lookups are done from
+// left to right, returning the first value found. Deadline and cancellation
+// are driven by the first context. If no context is passed,
+// context.Background() is returned.
+func MergeValuesN(ctxs ...context.Context) context.Context {
+	switch len(ctxs) {
+	case 0:
+		return context.Background()
+	case 1:
+		return ctxs[0]
+	}
+
+	others := make([]valuer, len(ctxs)-1)
+	for i, ctx := range ctxs[1:] {
+		others[i] = ctx
+	}
+	return &mergeValueNCtx{Context: ctxs[0], others: others}
+}
+
+func (c *mergeValueNCtx) Value(key interface{}) interface{} {
+	if val := c.Context.Value(key); val != nil {
+		return val
+	}
+	for _, other := range c.others {
+		if val := other.Value(key); val != nil {
+			return val
+		}
+	}
+	return nil
+}
+
+// WithValues creates a new context that stores all the key value pairs passed
+// in kv. The kv arguments must be alternating keys and values, otherwise
+// WithValues panics. If a key is passed multiple times, the last value is
+// used. Keys not present in kv are looked up in ctx.
+func WithValues(ctx context.Context, kv ...interface{}) context.Context {
+	if len(kv)%2 != 0 {
+		panic("ctxtool.WithValues: key value pairs incomplete")
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return &valuesCtx{Context: ctx, kv: kv}
+}
+
+func (c *valuesCtx) Value(key interface{}) interface{} {
+	for i := len(c.kv) - 2; i >= 0; i -= 2 {
+		if c.kv[i] == key {
+			return c.kv[i+1]
+		}
+	}
+	return c.Context.Value(key)
+}
@@ -19,6 +19,7 @@ package ctxtool
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -108,6 +109,30 @@ func TestMergeCancellation(t *testing.T) {
 				assert.Error(t, ctx.Err())
 			})
 
+			t.Run("canceller stops watcher of never cancelled contexts", func(t *testing.T) {
+				ctx1, cancel1 := context.WithCancel(context.Background())
+				ctx2, cancel2 := context.WithCancel(context.Background())
+				defer cancel1()
+				defer cancel2()
+
+				_, cancel := merger(ctx1, ctx2)
+				cancel()
+
+				// the parent contexts are still active, but the merged context must
+				// not leak its watcher go-routine.
+				goleak.VerifyNone(t)
+			})
+
+			t.Run("merging background contexts does not leak", func(t *testing.T) {
+				defer goleak.VerifyNone(t)
+
+				// background contexts are never cancelled. No watcher go-routine
+				// is required.
+				ctx, cancel := merger(context.Background(), context.Background())
+				defer cancel()
+				assert.Nil(t, ctx.Done())
+			})
+
 			t.Run("values are accessible", func(t *testing.T) {
 				defer goleak.VerifyNone(t)
 				ctx1 := contextWithValues("a", 1)
@@ -255,6 +280,289 @@ func TestMergeDeadline(t *testing.T) {
 
 }
 
+func TestMergeCancellationN(t *testing.T) {
+	mergers := map[string]func(ctxs ...context.Context) (context.Context, context.CancelFunc){
+		"MergeCancellationN": MergeCancellationN,
+		"MergeContextsN":     MergeContextsN,
+	}
+
+	for name, merger := range mergers {
+		t.Run(name, func(t *testing.T) {
+			t.Run("no context", func(t *testing.T) {
+				ctx, cancel := merger()
+				defer cancel()
+				assert.Nil(t, ctx.Done())
+				assert.NoError(t, ctx.Err())
+			})
+
+			for i := 0; i < 4; i++ {
+				i := i
+				t.Run(fmt.Sprintf("cancelling context %v cancels", i+1), func(t *testing.T) {
+					defer goleak.VerifyNone(t)
+
+					ctxs := make([]context.Context, 4)
+					cancels := make([]context.CancelFunc, 4)
+					for j := range ctxs {
+						ctxs[j], cancels[j] = context.WithCancel(context.Background())
+						defer cancels[j]()
+					}
+
+					ctx, cancel := merger(ctxs...)
+					defer cancel()
+
+					cancels[i]()
+					<-ctx.Done() // <- deadlock if cancel signal was not distributed
+					assert.Equal(t, context.Canceled, ctx.Err())
+				})
+			}
+
+			t.Run("canceller cancels new context", func(t *testing.T) {
+				defer goleak.VerifyNone(t)
+				ctx1, cancel1 := context.WithCancel(context.Background())
+				defer cancel1()
+
+				ctx, cancel := merger(ctx1, context.Background(), context.Background())
+				cancel()
+				<-ctx.Done()
+				assert.Equal(t, context.Canceled, ctx.Err())
+				assert.NoError(t, ctx1.Err())
+			})
+
+			t.Run("cancel if any context was canceled", func(t *testing.T) {
+				defer goleak.VerifyNone(t)
+				ctx3, cancelFn := context.WithCancel(context.Background())
+				cancelFn()
+
+				ctx, cancel := merger(context.Background(), context.Background(), ctx3)
+				defer cancel()
+				<-ctx.Done()
+				assert.Error(t, ctx.Err())
+			})
+
+			t.Run("never cancelled contexts do not start go-routine", func(t *testing.T) {
+				defer goleak.VerifyNone(t)
+				ctx, cancel := merger(context.Background(), context.Background(), context.Background())
+				defer cancel()
+				assert.Nil(t, ctx.Done())
+			})
+		})
+	}
+
+	t.Run("no context returns background", func(t *testing.T) {
+		ctx, cancel := MergeCancellationN()
+		cancel()
+		assert.Equal(t, context.Background(), ctx)
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("single context is returned as is", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		defer cancelParent()
+
+		ctx, cancel := MergeCancellationN(parent)
+		assert.Equal(t, parent, ctx)
+
+		cancel() // no-op
+		assert.NoError(t, ctx.Err())
+
+		cancelParent()
+		<-ctx.Done()
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("never cancelled contexts return first context", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		first := contextWithValues("a", 1)
+		ctx, cancel := MergeCancellationN(first, context.Background(), context.Background())
+		assert.Equal(t, first, ctx)
+
+		cancel() // no-op
+		assert.Nil(t, ctx.Done())
+		assert.NoError(t, ctx.Err())
+	})
+}
+
+func TestMergeValuesN(t *testing.T) {
+	t.Run("no context", func(t *testing.T) {
+		assert.Equal(t, context.Background(), MergeValuesN())
+	})
+
+	t.Run("lookup from left to right", func(t *testing.T) {
+		ctx := MergeValuesN(
+			contextWithValues("a", 1),
+			contextWithValues("a", 2, "b", 2),
+			contextWithValues("a", 3, "b", 3, "c", 3),
+		)
+		assert.Equal(t, 1, ctx.Value("a"))
+		assert.Equal(t, 2, ctx.Value("b"))
+		assert.Equal(t, 3, ctx.Value("c"))
+		assert.Nil(t, ctx.Value("d"))
+	})
+
+	t.Run("cancellation from first context only", func(t *testing.T) {
+		ctx2, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		ctx := MergeValuesN(context.Background(), ctx2, context.Background())
+		assert.NoError(t, ctx.Err())
+	})
+}
+
+func TestMergeContextsN(t *testing.T) {
+	t.Run("earliest deadline is used", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		now := time.Now()
+		ctx1, cancel1 := context.WithDeadline(context.Background(), now.Add(time.Hour))
+		defer cancel1()
+		ctx2, cancel2 := context.WithDeadline(context.Background(), now.Add(time.Minute))
+		defer cancel2()
+		ctx3, cancel3 := context.WithDeadline(context.Background(), now.Add(2*time.Hour))
+		defer cancel3()
+
+		ctx, cancel := MergeContextsN(ctx1, ctx2, ctx3)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, now.Add(time.Minute), deadline)
+	})
+
+	t.Run("values lookup from left to right", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := MergeContextsN(
+			contextWithValues("a", 1),
+			contextWithValues("a", 2, "b", 2),
+			contextWithValues("b", 3, "c", 3),
+		)
+		defer cancel()
+		assert.Equal(t, 1, ctx.Value("a"))
+		assert.Equal(t, 2, ctx.Value("b"))
+		assert.Equal(t, 3, ctx.Value("c"))
+	})
+}
+
+func TestWithEarliestDeadline(t *testing.T) {
+	t.Run("enforces deadline of context 1", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx1 := MergeDeadline(context.Background(), deadlineCtx(time.Now().Add(10*time.Millisecond)))
+		ctx2, cancel2 := context.WithCancel(context.Background())
+		defer cancel2()
+
+		ctx, cancel := WithEarliestDeadline(ctx1, ctx2)
+		defer cancel()
+
+		<-ctx.Done() // <- deadlock if deadline is not enforced
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+		assert.NoError(t, ctx2.Err())
+	})
+
+	t.Run("enforces deadline of context 2", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		defer cancel1()
+		ctx2 := MergeDeadline(context.Background(), deadlineCtx(time.Now().Add(10*time.Millisecond)))
+
+		ctx, cancel := WithEarliestDeadline(ctx1, ctx2)
+		defer cancel()
+
+		<-ctx.Done()
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	})
+
+	t.Run("reports earliest deadline", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		now := time.Now()
+		ctx1 := MergeDeadline(context.Background(), deadlineCtx(now.Add(time.Hour)))
+		ctx2 := MergeDeadline(context.Background(), deadlineCtx(now.Add(time.Minute)))
+
+		ctx, cancel := WithEarliestDeadline(ctx1, ctx2)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, now.Add(time.Minute), deadline)
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("no deadline", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := WithEarliestDeadline(context.Background(), context.Background())
+		defer cancel()
+
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		assert.Nil(t, ctx.Done())
+	})
+
+	t.Run("cancel by parent", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		ctx2 := MergeDeadline(context.Background(), deadlineCtx(time.Now().Add(time.Hour)))
+
+		ctx, cancel := WithEarliestDeadline(ctx1, ctx2)
+		defer cancel()
+
+		cancel1()
+		<-ctx.Done()
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("canceller cancels new context", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		defer cancel1()
+		ctx2 := MergeDeadline(context.Background(), deadlineCtx(time.Now().Add(time.Hour)))
+
+		ctx, cancel := WithEarliestDeadline(ctx1, ctx2)
+		cancel()
+		<-ctx.Done()
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+}
+
+func TestWithValues(t *testing.T) {
+	t.Run("values are accessible", func(t *testing.T) {
+		ctx := WithValues(context.Background(), "a", 1, "b", 2)
+		assert.Equal(t, 1, ctx.Value("a"))
+		assert.Equal(t, 2, ctx.Value("b"))
+		assert.Nil(t, ctx.Value("c"))
+	})
+
+	t.Run("later pairs take precedence", func(t *testing.T) {
+		ctx := WithValues(context.Background(), "a", 1, "a", 2)
+		assert.Equal(t, 2, ctx.Value("a"))
+	})
+
+	t.Run("overwrites parent values", func(t *testing.T) {
+		ctx := WithValues(contextWithValues("a", 1, "b", 1), "a", 2)
+		assert.Equal(t, 2, ctx.Value("a"))
+		assert.Equal(t, 1, ctx.Value("b"))
+	})
+
+	t.Run("keeps parent cancellation", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		ctx := WithValues(parent, "a", 1)
+		cancel()
+		<-ctx.Done()
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("panics on incomplete pairs", func(t *testing.T) {
+		assert.Panics(t, func() {
+			WithValues(context.Background(), "a", 1, "b")
+		})
+	})
+}
+
 func contextWithValues(args ...interface{}) context.Context {
 	if len(args)%2 != 0 {
 		panic("key values pairs incomplete")
@@ -266,3 +574,10 @@ func contextWithValues(args ...interface{}) context.Context {
 	}
 	return ctx
 }
+
+// deadlineCtx reports a deadline without enforcing it.
+type deadlineCtx time.Time
+
+func (d deadlineCtx) Deadline() (time.Time, bool) {
+	return time.Time(d), true
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"sync"
 
 	"github.com/elastic/go-concert/ctxtool"
 	"github.com/elastic/go-concert/unison"
@@ -45,8 +46,60 @@ import (
 //		}
 //  }
 func WithSignal(parent unison.Canceler, sigs ...os.Signal) (context.Context, context.CancelFunc) {
+	return WithSignalExit(parent, 3, sigs...)
+}
+
+// osExit is used to force shutdown the process. It can be replaced in tests.
+var osExit = os.Exit
+
+// WithSignalExit creates a context that will be cancelled if any of the
+// configured signals is received by the process, like WithSignal. If the
+// signal is received again, the process is force shutdown with exitCode.
+//
+// If exitCode is < 0, the process is not force shutdown on the second signal.
+// Further signals are ignored until the cancel function is called in that case.
+func WithSignalExit(parent unison.Canceler, exitCode int, sigs ...os.Signal) (context.Context, context.CancelFunc) {
+	ctx, cancel, _ := withSignal(parent, exitCode, sigs...)
+	return ctx, cancel
+}
+
+// WithSignalValue creates a context that will be cancelled if any of the
+// configured signals is received by the process, like WithSignal. The
+// returned accessor reports the signal that triggered the cancellation, or
+// nil if the context has not been cancelled by a signal. The accessor is safe
+// to be called concurrently.
+//
+// example:
+//
+//	ctx, cancel, received := osctx.WithSignalValue(context.Background(), os.Interrupt, syscall.SIGTERM)
+//	defer cancel()
+//	<-ctx.Done()
+//	log.Printf("shutting down, received signal: %v", received())
+func WithSignalValue(parent unison.Canceler, sigs ...os.Signal) (context.Context, context.CancelFunc, func() os.Signal) {
+	return withSignal(parent, 3, sigs...)
+}
+
+func withSignal(parent unison.Canceler, exitCode int, sigs ...os.Signal) (context.Context, context.CancelFunc, func() os.Signal) {
+	var mu sync.Mutex
+	var received os.Signal
+	getReceived := func() os.Signal {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+
 	ctx, cancel := context.WithCancel(ctxtool.FromCanceller(parent))
+	stop := make(chan struct{})
+	var stopOnce sync.Once
 	ch := make(chan os.Signal, 1)
+	stopFn := func() {
+		// remove the handler right away, so no signal is forwarded to the
+		// go-routine after the caller did cancel.
+		signal.Stop(ch)
+		stopOnce.Do(func() { close(stop) })
+		cancel()
+	}
+
 	go func() {
 		defer func() {
 			signal.Stop(ch)
@@ -56,14 +109,74 @@ func WithSignal(parent unison.Canceler, sigs ...os.Signal) (context.Context, con
 		select {
 		case <-ctx.Done():
 			return
-		case <-ch:
+		case sig := <-ch:
+			// record the signal before cancelling, so it is visible to
+			// go-routines observing the context.
+			mu.Lock()
+			received = sig
+			mu.Unlock()
+
 			cancel()
+			if exitCode < 0 {
+				// ignore further signals until cleanup
+				for {
+					select {
+					case <-stop:
+						return
+					case <-ch:
+					}
+				}
+			}
+
 			// force shutdown in case we receive another signal
-			<-ch
-			os.Exit(3)
+			select {
+			case <-stop:
+				return
+			case <-ch:
+				select {
+				case <-stop: // cancelled concurrently
+					return
+				default:
+					osExit(exitCode)
+				}
+			}
 		}
 	}()
 
 	signal.Notify(ch, sigs...)
-	return ctx, cancel
+	return ctx, stopFn, getReceived
+}
+
+// NotifyChannel forwards the configured signals onto the returned channel.
+// Unlike WithSignal the process is never force shutdown, which makes
+// NotifyChannel suitable for signals like SIGHUP, that are used to trigger a
+// reload. Signals are coalesced if the consumer is not ready to receive them.
+//
+// The signal handler is removed and the returned channel is closed once parent
+// gets cancelled or when the cancel function is called.
+func NotifyChannel(parent unison.Canceler, sigs ...os.Signal) (<-chan os.Signal, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctxtool.FromCanceller(parent))
+
+	ch := make(chan os.Signal, 1)
+	out := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	go func() {
+		defer close(out)
+		defer signal.Stop(ch)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-ch:
+				select {
+				case out <- sig:
+				default:
+					// signal is still pending in out
+				}
+			}
+		}
+	}()
+
+	return out, cancel
 }
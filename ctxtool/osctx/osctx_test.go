@@ -24,6 +24,7 @@ import (
 	"os"
 	"syscall"
 	"testing"
+	"time"
 )
 
 func TestWithSignal(t *testing.T) {
@@ -53,3 +54,121 @@ func TestWithSignal(t *testing.T) {
 		<-ctx.Done() // must not block, as the signal has been delivered.
 	})
 }
+
+func TestWithSignalExit(t *testing.T) {
+	withExit := func(fn func(int)) func() {
+		old := osExit
+		osExit = fn
+		return func() { osExit = old }
+	}
+
+	t.Run("force exit with exit code on second signal", func(t *testing.T) {
+		testSignal := syscall.SIGWINCH
+
+		codes := make(chan int, 1)
+		defer withExit(func(code int) { codes <- code })()
+
+		ctx, cancel := WithSignalExit(context.Background(), 42, testSignal)
+		defer cancel()
+
+		syscall.Kill(syscall.Getpid(), testSignal)
+		<-ctx.Done()
+
+		syscall.Kill(syscall.Getpid(), testSignal)
+		if code := <-codes; code != 42 {
+			t.Fatalf("expected exit code 42, got %v", code)
+		}
+	})
+
+	t.Run("do not exit with negative exit code", func(t *testing.T) {
+		testSignal := syscall.SIGWINCH
+
+		defer withExit(func(code int) {
+			t.Errorf("unexpected exit with code %v", code)
+		})()
+
+		ctx, cancel := WithSignalExit(context.Background(), -1, testSignal)
+		syscall.Kill(syscall.Getpid(), testSignal)
+		<-ctx.Done()
+
+		syscall.Kill(syscall.Getpid(), testSignal)
+		syscall.Kill(syscall.Getpid(), testSignal)
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	})
+
+	t.Run("do not exit after cancel", func(t *testing.T) {
+		testSignal := syscall.SIGWINCH
+
+		defer withExit(func(code int) {
+			t.Errorf("unexpected exit with code %v", code)
+		})()
+
+		ctx, cancel := WithSignalExit(context.Background(), 42, testSignal)
+		syscall.Kill(syscall.Getpid(), testSignal)
+		<-ctx.Done()
+		cancel()
+
+		syscall.Kill(syscall.Getpid(), testSignal)
+		time.Sleep(10 * time.Millisecond)
+	})
+}
+
+func TestWithSignalValue(t *testing.T) {
+	t.Run("no signal on explicit cancel", func(t *testing.T) {
+		ctx, cancel, received := WithSignalValue(context.Background(), syscall.SIGUSR1)
+		cancel()
+		<-ctx.Done()
+		if sig := received(); sig != nil {
+			t.Fatalf("unexpected signal %v", sig)
+		}
+	})
+
+	t.Run("report received signal", func(t *testing.T) {
+		testSignal := syscall.SIGWINCH
+
+		ctx, cancel, received := WithSignalValue(context.Background(), syscall.SIGUSR1, testSignal)
+		defer cancel()
+
+		syscall.Kill(syscall.Getpid(), testSignal)
+		<-ctx.Done()
+		if sig := received(); sig != testSignal {
+			t.Fatalf("expected signal %v, got %v", testSignal, sig)
+		}
+	})
+}
+
+func TestNotifyChannel(t *testing.T) {
+	t.Run("close channel if parent context is cancelled", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		ch, cancel := NotifyChannel(parent, syscall.SIGHUP)
+		defer cancel()
+
+		for range ch {
+		}
+	})
+
+	t.Run("close channel on explicit cancel", func(t *testing.T) {
+		ch, cancel := NotifyChannel(context.Background(), syscall.SIGHUP)
+		cancel()
+
+		for range ch {
+		}
+	})
+
+	t.Run("forward signals", func(t *testing.T) {
+		testSignal := syscall.SIGHUP
+
+		ch, cancel := NotifyChannel(context.Background(), testSignal)
+		defer cancel()
+
+		for i := 0; i < 3; i++ {
+			syscall.Kill(syscall.Getpid(), testSignal)
+			if sig := <-ch; sig != testSignal {
+				t.Fatalf("expected signal %v, got %v", testSignal, sig)
+			}
+		}
+	})
+}
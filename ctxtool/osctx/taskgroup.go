@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package osctx
+
+import (
+	"context"
+	"os"
+
+	"github.com/elastic/go-concert/unison"
+)
+
+// TaskGroupWithSignals creates a TaskGroup that is signaled to shutdown when
+// any of the configured signals is received by the process. Wait on the
+// TaskGroup returns after all workers did return.
+//
+// The process is force shutdown with exit code 3 if a signal is received
+// again. Use TaskGroupWithSignalsExit to configure the exit code.
+//
+// The cancel function removes the signal handler and must be called in order
+// to clean up associated resources.
+//
+// example:
+//
+//	func main() {
+//		tg, cancel := osctx.TaskGroupWithSignals(os.Interrupt, syscall.SIGTERM)
+//		defer cancel()
+//
+//		tg.Go(worker1)
+//		tg.Go(worker2)
+//		if err := tg.Wait(); err != nil {
+//			...
+//		}
+//	}
+func TaskGroupWithSignals(sigs ...os.Signal) (*unison.TaskGroup, context.CancelFunc) {
+	return TaskGroupWithSignalsExit(3, sigs...)
+}
+
+// TaskGroupWithSignalsExit creates a TaskGroup that is signaled to shutdown
+// when any of the configured signals is received by the process. If a signal is
+// received again, the process is force shutdown with the given exit code. If
+// exitCode is < 0, the process will not be force shutdown.
+func TaskGroupWithSignalsExit(exitCode int, sigs ...os.Signal) (*unison.TaskGroup, context.CancelFunc) {
+	ctx, cancel := WithSignalExit(context.Background(), exitCode, sigs...)
+	return unison.TaskGroupWithCancel(ctx), cancel
+}
@@ -0,0 +1,62 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package osctx
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskGroupWithSignals(t *testing.T) {
+	t.Run("stop on cancel", func(t *testing.T) {
+		tg, cancel := TaskGroupWithSignals(syscall.SIGUSR2)
+		defer cancel()
+
+		tg.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		})
+
+		cancel()
+		require.NoError(t, tg.Wait())
+	})
+
+	t.Run("stop on signal", func(t *testing.T) {
+		testSignal := syscall.SIGUSR2
+
+		tg, cancel := TaskGroupWithSignalsExit(-1, testSignal)
+		defer cancel()
+
+		started := make(chan struct{})
+		tg.Go(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return nil
+		})
+
+		<-started
+		syscall.Kill(syscall.Getpid(), testSignal)
+		require.NoError(t, tg.Wait()) // must not block, as the signal has been delivered.
+	})
+}
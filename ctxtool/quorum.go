@@ -0,0 +1,126 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ctxtool
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+type quorumCtx struct {
+	context.Context
+	ch <-chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// MergeQuorum creates a new context that will be cancelled once at least n of
+// the input contexts have been cancelled. The context reports the error of
+// the context that completed the quorum. Values are looked up in the first
+// context. The new context has no deadline.
+//
+// If n <= 0, the returned context is already cancelled. If n is larger than
+// the number of contexts, the new context is only cancelled by the returned
+// CancelFunc.
+//
+// Only a single go-routine is used to wait for cancellation, independent of
+// the number of contexts.
+func MergeQuorum(n int, ctxs ...context.Context) (context.Context, context.CancelFunc) {
+	var parent context.Context = context.Background()
+	if len(ctxs) > 0 {
+		parent = ctxs[0]
+	}
+	return mergeQuorum(Detach(parent), n, ctxs)
+}
+
+// mergeQuorum implements MergeQuorum and MergeCancellationN. Values and
+// Deadline are taken from parent.
+func mergeQuorum(parent context.Context, n int, ctxs []context.Context) (context.Context, context.CancelFunc) {
+	if n <= 0 {
+		return &quorumCtx{Context: parent, ch: closedChan, err: context.Canceled}, func() {}
+	}
+
+	var pending []canceller
+	for _, ctx := range ctxs {
+		if err := ctx.Err(); err != nil {
+			if n--; n == 0 {
+				return &quorumCtx{Context: parent, ch: closedChan, err: err}, func() {}
+			}
+		} else if ctx.Done() != nil {
+			pending = append(pending, ctx)
+		}
+	}
+
+	chDone := make(chan struct{})
+	merged := &quorumCtx{Context: parent, ch: chDone}
+	if n <= len(pending) {
+		go merged.waitQuorum(chDone, pending, n)
+	}
+
+	canceller := func() {
+		merged.mu.Lock()
+		defer merged.mu.Unlock()
+		if merged.err == nil {
+			merged.err = context.Canceled
+			close(chDone)
+		}
+	}
+	return merged, canceller
+}
+
+func (c *quorumCtx) waitQuorum(chDone chan struct{}, pending []canceller, n int) {
+	cases := make([]reflect.SelectCase, 0, len(pending)+1)
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(chDone)})
+	for _, ctx := range pending {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	}
+
+	var err error
+	for n > 0 {
+		chosen, _, _ := reflect.Select(cases)
+		if chosen == 0 {
+			return // CancelFunc triggered cleanup
+		}
+
+		err = pending[chosen-1].Err()
+		n--
+
+		// stop waiting on the cancelled context
+		cases = append(cases[:chosen], cases[chosen+1:]...)
+		pending = append(pending[:chosen-1], pending[chosen:]...)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+		close(chDone)
+	}
+}
+
+func (c *quorumCtx) Done() <-chan struct{} {
+	return c.ch
+}
+
+func (c *quorumCtx) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
@@ -0,0 +1,138 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ctxtool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMergeQuorum(t *testing.T) {
+	makeContexts := func(n int) ([]context.Context, []context.CancelFunc) {
+		ctxs := make([]context.Context, n)
+		cancels := make([]context.CancelFunc, n)
+		for i := range ctxs {
+			ctxs[i], cancels[i] = context.WithCancel(context.Background())
+		}
+		return ctxs, cancels
+	}
+	cancelAll := func(cancels []context.CancelFunc) {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+
+	t.Run("cancel once quorum is reached", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctxs, cancels := makeContexts(4)
+		defer cancelAll(cancels)
+
+		ctx, cancel := MergeQuorum(2, ctxs...)
+		defer cancel()
+
+		cancels[3]()
+		select {
+		case <-ctx.Done():
+			t.Fatal("context cancelled before quorum was reached")
+		case <-time.After(10 * time.Millisecond):
+		}
+		assert.NoError(t, ctx.Err())
+
+		cancels[1]()
+		<-ctx.Done() // <- deadlock if quorum is not detected
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("reports error of context completing the quorum", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel2()
+
+		ctx, cancel := MergeQuorum(2, ctx1, ctx2)
+		defer cancel()
+
+		cancel1()
+		<-ctx.Done()
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	})
+
+	t.Run("counts already cancelled contexts", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctxs, cancels := makeContexts(3)
+		defer cancelAll(cancels)
+		cancels[0]()
+		cancels[1]()
+
+		ctx, cancel := MergeQuorum(2, ctxs...)
+		defer cancel()
+		<-ctx.Done()
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("canceller cancels new context", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctxs, cancels := makeContexts(3)
+		defer cancelAll(cancels)
+
+		ctx, cancel := MergeQuorum(2, ctxs...)
+		cancel()
+		<-ctx.Done()
+		assert.Equal(t, context.Canceled, ctx.Err())
+		for _, ctx := range ctxs {
+			assert.NoError(t, ctx.Err())
+		}
+	})
+
+	t.Run("unreachable quorum only cancelled by canceller", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctxs, cancels := makeContexts(2)
+		ctx, cancel := MergeQuorum(3, ctxs...)
+		cancelAll(cancels)
+		assert.NoError(t, ctx.Err())
+
+		cancel()
+		<-ctx.Done()
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("non-positive quorum is cancelled", func(t *testing.T) {
+		ctx, cancel := MergeQuorum(0, context.Background())
+		defer cancel()
+		<-ctx.Done()
+		assert.Error(t, ctx.Err())
+	})
+
+	t.Run("values are looked up in first context", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := MergeQuorum(1, contextWithValues("a", 1), contextWithValues("a", 2, "b", 2))
+		defer cancel()
+		assert.Equal(t, 1, ctx.Value("a"))
+		assert.Nil(t, ctx.Value("b"))
+	})
+}
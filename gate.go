@@ -0,0 +1,102 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package concert
+
+import (
+	"sync"
+)
+
+// Gate lets all go-routines pass while it is open, and blocks them while it
+// is closed. Unlike a mutex or semaphore, any number of go-routines can pass
+// the Gate concurrently once it is open.
+//
+// The zero value of Gate is valid and closed. A Gate must not be copied
+// after first use.
+type Gate struct {
+	mu   sync.Mutex
+	open bool
+
+	// waiter is closed by Open, releasing all go-routines blocked in Enter.
+	// waiter is nil while the gate is open, or if no go-routine is blocked.
+	waiter chan struct{}
+}
+
+// doneContext is a subset of context.Context, to allow more restrained
+// cancellation types as well.
+type doneContext interface {
+	Done() <-chan struct{}
+	Err() error
+}
+
+// Open opens the gate and releases all go-routines blocked in Enter.
+func (g *Gate) Open() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.open = true
+	if g.waiter != nil {
+		close(g.waiter)
+		g.waiter = nil
+	}
+}
+
+// Close closes the gate. Subsequent calls to Enter block until the gate is
+// opened again.
+func (g *Gate) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.open = false
+}
+
+// IsOpen reports whether the gate is currently open.
+func (g *Gate) IsOpen() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.open
+}
+
+// Enter returns immediately if the gate is open, and blocks while the gate
+// is closed. Enter returns nil once the gate has been opened, or the error
+// returned by ctx.Err() if ctx has been cancelled first. All go-routines
+// blocked in Enter pass the gate on Open, even if the gate is closed again
+// right after.
+func (g *Gate) Enter(ctx doneContext) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	g.mu.Lock()
+	if g.open {
+		g.mu.Unlock()
+		return nil
+	}
+	if g.waiter == nil {
+		g.waiter = make(chan struct{})
+	}
+	waiter := g.waiter
+	g.mu.Unlock()
+
+	select {
+	case <-waiter:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
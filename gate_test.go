@@ -0,0 +1,113 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package concert
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGate(t *testing.T) {
+	t.Run("zero value is closed", func(t *testing.T) {
+		var g Gate
+		assert.False(t, g.IsOpen())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, g.Enter(ctx))
+	})
+
+	t.Run("enter returns immediately if open", func(t *testing.T) {
+		var g Gate
+		g.Open()
+		assert.True(t, g.IsOpen())
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, g.Enter(context.Background()))
+		}
+	})
+
+	t.Run("open releases all waiters", func(t *testing.T) {
+		const waiters = 5
+
+		var g Gate
+		var wg sync.WaitGroup
+		errs := make(chan error, waiters)
+		for i := 0; i < waiters; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- g.Enter(context.Background())
+			}()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		g.Open()
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("toggling", func(t *testing.T) {
+		var g Gate
+		g.Open()
+		assert.NoError(t, g.Enter(context.Background()))
+
+		g.Close()
+		assert.False(t, g.IsOpen())
+
+		entered := make(chan error)
+		go func() { entered <- g.Enter(context.Background()) }()
+
+		select {
+		case <-entered:
+			t.Fatal("entered closed gate")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		g.Open()
+		assert.NoError(t, <-entered)
+	})
+
+	t.Run("cancel blocked enter", func(t *testing.T) {
+		var g Gate
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+		assert.Equal(t, context.Canceled, g.Enter(ctx))
+
+		// gate is still usable after a waiter has been cancelled
+		g.Open()
+		assert.NoError(t, g.Enter(context.Background()))
+	})
+
+	t.Run("cancelled context fails on open gate", func(t *testing.T) {
+		var g Gate
+		g.Open()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Equal(t, context.Canceled, g.Enter(ctx))
+	})
+}
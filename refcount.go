@@ -51,6 +51,25 @@ func (c *RefCount) Retain() {
 	}
 }
 
+// TryRetain increases the ref count, unless the reference counter has
+// already been released. TryRetain returns false if the reference counter is
+// in the 'free' state, in which case the caller must treat the resource as
+// gone.
+//
+// TryRetain is safe to call concurrently with Release, and should be used
+// when looking up a shared resource that might be released concurrently.
+func (c *RefCount) TryRetain() bool {
+	for {
+		current := c.count.Load()
+		if current == refCountFree {
+			return false
+		}
+		if c.count.CompareAndSwap(current, current+1) {
+			return true
+		}
+	}
+}
+
 // Release decreases the reference count. It returns true, if the reference count
 // has reached a 'free' state.
 // Releasing a reference count in a free state will trigger a panic.
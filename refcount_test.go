@@ -20,6 +20,8 @@ package concert_test
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -64,6 +66,47 @@ func TestRefCount(t *testing.T) {
 		})
 	})
 
+	t.Run("try retain on live refcount succeeds", func(t *testing.T) {
+		var r concert.RefCount
+		assert.True(t, r.TryRetain())
+		assert.False(t, r.Release())
+		assert.True(t, r.Release())
+	})
+
+	t.Run("try retain on released refcount fails", func(t *testing.T) {
+		var r concert.RefCount
+		r.Release()
+		assert.False(t, r.TryRetain())
+		assert.NotPanics(t, func() { r.TryRetain() })
+	})
+
+	t.Run("try retain races with release", func(t *testing.T) {
+		const workers = 8
+
+		for i := 0; i < 100; i++ {
+			var actions atomic.Int32
+			r := concert.RefCount{
+				Action: func(_ error) { actions.Add(1) },
+			}
+
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if r.TryRetain() {
+						r.Release()
+					}
+				}()
+			}
+			r.Release()
+			wg.Wait()
+
+			assert.Equal(t, int32(1), actions.Load())
+			assert.False(t, r.TryRetain())
+		}
+	})
+
 	t.Run("fail passes error releases the refcount", func(t *testing.T) {
 		var released bool
 		errTest := errors.New("test")
@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package concert
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ShutdownResult collects the errors reported by multiple subsystems during
+// shutdown into a single error. Each error is labeled with the name of its
+// source.
+//
+// Example:
+//
+//	var result concert.ShutdownResult
+//	result.Add("workers", taskGroup.Stop())
+//	result.AddAll("fetchers", multiErrGroup.Wait())
+//	return result.Err()
+//
+// The zero value of ShutdownResult is valid. ShutdownResult can be updated
+// concurrently.
+type ShutdownResult struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// Add records the error reported by source. Add ignores nil errors.
+func (r *ShutdownResult) Add(source string, err error) {
+	if err == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, fmt.Errorf("%s: %w", source, err))
+}
+
+// AddAll records all errors reported by source. Each error is labeled with
+// the source name. Nil errors are ignored.
+func (r *ShutdownResult) AddAll(source string, errs []error) {
+	for _, err := range errs {
+		r.Add(source, err)
+	}
+}
+
+// Err returns all recorded errors combined via errors.Join, in the order
+// they have been recorded. Err returns nil if no error has been recorded.
+func (r *ShutdownResult) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return errors.Join(r.errs...)
+}
@@ -0,0 +1,75 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package concert_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-concert"
+	"github.com/elastic/go-concert/unison"
+)
+
+func TestShutdownResult(t *testing.T) {
+	t.Run("no errors", func(t *testing.T) {
+		var result concert.ShutdownResult
+		result.Add("a", nil)
+		result.AddAll("b", nil)
+		assert.NoError(t, result.Err())
+	})
+
+	t.Run("errors are labeled with source", func(t *testing.T) {
+		errA, errB := errors.New("oops A"), errors.New("oops B")
+
+		var result concert.ShutdownResult
+		result.Add("a", errA)
+		result.Add("b", errB)
+
+		err := result.Err()
+		assert.True(t, errors.Is(err, errA))
+		assert.True(t, errors.Is(err, errB))
+		assert.Equal(t, "a: oops A\nb: oops B", err.Error())
+	})
+
+	t.Run("combine subsystems", func(t *testing.T) {
+		errWorker := errors.New("worker failed")
+		errFetch1, errFetch2 := errors.New("fetch 1 failed"), errors.New("fetch 2 failed")
+
+		var tg unison.TaskGroup
+		tg.Go(func(_ context.Context) error { return errWorker })
+
+		var grp unison.MultiErrGroup
+		grp.Go(func() error { return errFetch1 })
+		grp.Go(func() error { return errFetch2 })
+
+		var result concert.ShutdownResult
+		result.Add("worker pool", tg.Wait())
+		result.AddAll("fetchers", grp.Wait())
+
+		err := result.Err()
+		for _, want := range []error{errWorker, errFetch1, errFetch2} {
+			assert.True(t, errors.Is(err, want), "missing error: %v", want)
+		}
+		assert.Contains(t, err.Error(), "worker pool: task failures: worker failed")
+		assert.Contains(t, err.Error(), "fetchers: fetch 1 failed")
+		assert.Contains(t, err.Error(), "fetchers: fetch 2 failed")
+	})
+}
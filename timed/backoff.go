@@ -0,0 +1,137 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package timed
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff computes exponentially growing delays between retries. The delay
+// starts with Initial and is multiplied by Multiplier on each attempt, until
+// Max is reached. Backoff can be used as is, or its Duration method can be
+// passed to RetryUntilBackoff or unison.TaskGroup.RestartBackoff.
+//
+// Backoff is safe for concurrent use. The configuration fields must not be
+// modified after first use.
+//
+// Example:
+//
+//	b := &timed.Backoff{Initial: 100 * time.Millisecond, Max: 10 * time.Second, Jitter: 0.2}
+//	for {
+//		if err := connect(ctx); err == nil {
+//			b.Reset()
+//			...
+//		}
+//		if err := timed.WaitBackoff(ctx, b); err != nil {
+//			return err
+//		}
+//	}
+type Backoff struct {
+	// Initial configures the delay of the first attempt.
+	Initial time.Duration
+
+	// Max caps the delay. If 0, the delay is not capped.
+	Max time.Duration
+
+	// Multiplier configures the growth of the delay per attempt. If 0, a
+	// multiplier of 2 is used.
+	Multiplier float64
+
+	// Jitter randomizes the delay to delay ± rand*Jitter*delay. Jitter is
+	// limited to the range [0, 1]. The jittered delay never exceeds Max.
+	Jitter float64
+
+	mu      sync.Mutex
+	attempt int
+	rng     *rand.Rand
+}
+
+// Next returns the delay for the next attempt and increments the attempt
+// counter.
+func (b *Backoff) Next() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.attempt++
+	return b.duration(b.attempt)
+}
+
+// Reset resets the attempt counter, such that the next call to Next returns
+// the initial delay again.
+func (b *Backoff) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.attempt = 0
+}
+
+// Duration returns the delay for the given attempt, starting with 1 for the
+// initial delay. Duration does not modify the attempt counter used by Next.
+func (b *Backoff) Duration(attempt int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.duration(attempt)
+}
+
+func (b *Backoff) duration(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	multiplier := b.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	// Clamp the delay before applying jitter. The delay can overflow to +Inf,
+	// which would turn into NaN when scaled by the jitter.
+	d := float64(b.Initial) * math.Pow(multiplier, float64(attempt-1))
+	switch {
+	case math.IsNaN(d):
+		d = 0
+	case b.Max > 0 && d > float64(b.Max):
+		d = float64(b.Max)
+	case d > math.MaxInt64:
+		d = math.MaxInt64
+	}
+
+	if jitter := math.Min(math.Max(b.Jitter, 0), 1); jitter > 0 {
+		if b.rng == nil {
+			b.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		d += (2*b.rng.Float64() - 1) * jitter * d
+	}
+
+	switch {
+	case b.Max > 0 && d > float64(b.Max):
+		return b.Max
+	case d >= math.MaxInt64:
+		return time.Duration(math.MaxInt64)
+	case d < 0:
+		return 0
+	}
+	return time.Duration(d)
+}
+
+// WaitBackoff blocks for the next delay computed by b, or until the passed
+// context signals cancellation. WaitBackoff returns ctx.Err() if the context
+// got cancelled early.
+func WaitBackoff(ctx canceler, b *Backoff) error {
+	return Wait(ctx, b.Next())
+}
@@ -0,0 +1,119 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package timed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Run("grows exponentially", func(t *testing.T) {
+		b := &Backoff{Initial: time.Second, Multiplier: 3}
+		assert.Equal(t, 1*time.Second, b.Next())
+		assert.Equal(t, 3*time.Second, b.Next())
+		assert.Equal(t, 9*time.Second, b.Next())
+	})
+
+	t.Run("default multiplier is 2", func(t *testing.T) {
+		b := &Backoff{Initial: time.Second}
+		assert.Equal(t, 1*time.Second, b.Next())
+		assert.Equal(t, 2*time.Second, b.Next())
+		assert.Equal(t, 4*time.Second, b.Next())
+	})
+
+	t.Run("capped at max", func(t *testing.T) {
+		b := &Backoff{Initial: time.Second, Max: 5 * time.Second}
+		for i := 0; i < 3; i++ {
+			b.Next()
+		}
+		assert.Equal(t, 5*time.Second, b.Next())
+		assert.Equal(t, 5*time.Second, b.Duration(1000))
+	})
+
+	t.Run("does not overflow without max", func(t *testing.T) {
+		b := &Backoff{Initial: time.Second}
+		assert.Equal(t, time.Duration(1<<63-1), b.Duration(1000))
+	})
+
+	t.Run("jitter on overflowing delay is bounded", func(t *testing.T) {
+		b := &Backoff{Initial: time.Second, Multiplier: 1e300, Jitter: 1}
+		for i := 0; i < 1000; i++ {
+			d := b.Duration(10)
+			assert.True(t, d >= 0, "duration %v out of bounds", d)
+		}
+	})
+
+	t.Run("reset starts with initial delay", func(t *testing.T) {
+		b := &Backoff{Initial: time.Second}
+		b.Next()
+		b.Next()
+		b.Reset()
+		assert.Equal(t, 1*time.Second, b.Next())
+	})
+
+	t.Run("duration does not modify attempt counter", func(t *testing.T) {
+		b := &Backoff{Initial: time.Second}
+		assert.Equal(t, 4*time.Second, b.Duration(3))
+		assert.Equal(t, 1*time.Second, b.Next())
+	})
+
+	t.Run("jitter stays within bounds", func(t *testing.T) {
+		b := &Backoff{Initial: 100 * time.Millisecond, Max: 105 * time.Millisecond, Jitter: 0.1}
+		for i := 0; i < 1000; i++ {
+			d := b.Duration(1)
+			assert.True(t, 90*time.Millisecond <= d && d <= 105*time.Millisecond, "duration %v out of bounds", d)
+		}
+	})
+
+	t.Run("usable with RetryUntilBackoff", func(t *testing.T) {
+		b := &Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond}
+		count := 0
+		err := RetryUntilBackoff(context.Background(), time.Hour, b.Duration, func(_ canceler) error {
+			count++
+			if count < 3 {
+				return errors.New("oops")
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+}
+
+func TestWaitBackoff(t *testing.T) {
+	t.Run("waits for next delay", func(t *testing.T) {
+		b := &Backoff{Initial: 10 * time.Millisecond}
+		start := time.Now()
+		assert.NoError(t, WaitBackoff(context.Background(), b))
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(10*time.Millisecond))
+		assert.Equal(t, 20*time.Millisecond, b.Next())
+	})
+
+	t.Run("returns with error on cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		b := &Backoff{Initial: time.Hour}
+		assert.Equal(t, context.Canceled, WaitBackoff(ctx, b))
+	})
+}
@@ -20,6 +20,7 @@ package timed
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/elastic/go-concert/ctxtool"
@@ -54,6 +55,24 @@ func Wait(ctx canceler, duration time.Duration) error {
 	}
 }
 
+// WaitRemaining blocks for the configured duration or until the passed
+// context signals cancellation, like Wait. If the context got cancelled early,
+// WaitRemaining returns the time that was left to wait and ctx.Err(). If the
+// duration has passed, WaitRemaining returns 0 and nil.
+func WaitRemaining(ctx canceler, duration time.Duration) (time.Duration, error) {
+	start := time.Now()
+	if err := Wait(ctx, duration); err != nil {
+		remaining := duration - time.Since(start)
+		if remaining <= 0 {
+			// the timer was about to fire. Report the smallest possible
+			// duration, so to indicate the wait has been interrupted.
+			remaining = time.Nanosecond
+		}
+		return remaining, err
+	}
+	return 0, nil
+}
+
 // Periodic executes fn on every period. Periodic returns if the context is
 // cancelled.
 // The underlying ticket adjusts the intervals or drops ticks to make up for
@@ -90,6 +109,180 @@ func Periodic(ctx canceler, period time.Duration, fn func() error) error {
 	}
 }
 
+// PeriodicImmediate behaves like Periodic, but executes fn once right away,
+// before waiting for the first period to pass. If the context is already
+// cancelled, fn is not executed at all.
+func PeriodicImmediate(ctx canceler, period time.Duration, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return Periodic(ctx, period, fn)
+}
+
+// PeriodicJitter behaves like Periodic, but the delay before each run of fn
+// is randomized to period ± rand*jitter. This avoids many instances started at
+// the same time to synchronize their runs. Other than Periodic, the delay is
+// always measured from the end of the last run of fn.
+// The jittered delay is never less than 1ns. The period must be greater than
+// 0, otherwise PeriodicJitter panics.
+func PeriodicJitter(ctx canceler, period, jitter time.Duration, fn func() error) error {
+	if period <= 0 {
+		panic("non-positive period for PeriodicJitter")
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	timer := time.NewTimer(jitterDuration(rng, period, jitter))
+	defer timer.Stop()
+
+	done := ctx.Done()
+	for {
+		// always check for cancel first, to not accidentally trigger another run if
+		// the context is already cancelled, but the timer did fire already.
+		select {
+		case <-done:
+			return ctx.Err()
+		default:
+		}
+
+		select {
+		case <-timer.C:
+			if err := fn(); err != nil {
+				return err
+			}
+			timer.Reset(jitterDuration(rng, period, jitter))
+		case <-done:
+			return ctx.Err()
+		}
+	}
+}
+
+func jitterDuration(rng *rand.Rand, period, jitter time.Duration) time.Duration {
+	d := period
+	if jitter > 0 {
+		d += time.Duration((2*rng.Float64() - 1) * float64(jitter))
+	}
+	if d <= 0 {
+		d = time.Nanosecond
+	}
+	return d
+}
+
+// Debounce calls fn once no new signal has been received from trigger for the
+// quiet duration. Every signal from trigger resets the quiet period, such
+// that bursts of signals are collapsed into a single call of fn.
+//
+// Debounce returns if the context is cancelled, or if fn returns an error. If
+// trigger is closed, Debounce runs a pending call of fn after the quiet period
+// and returns nil.
+func Debounce(ctx canceler, quiet time.Duration, trigger <-chan struct{}, fn func() error) error {
+	var timer *time.Timer
+	var fire <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	done := ctx.Done()
+	for {
+		select {
+		case <-done:
+			return ctx.Err()
+		default:
+		}
+
+		select {
+		case <-done:
+			return ctx.Err()
+
+		case _, ok := <-trigger:
+			if !ok {
+				trigger = nil
+				if fire == nil {
+					return nil
+				}
+				continue
+			}
+
+			// Use a new timer instead of resetting the old one, so we do not
+			// need to care about draining the timer channel.
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.NewTimer(quiet)
+			fire = timer.C
+
+		case <-fire:
+			fire = nil
+			if err := fn(); err != nil {
+				return err
+			}
+			if trigger == nil {
+				return nil
+			}
+		}
+	}
+}
+
+// Throttle calls fn at most once per interval when receiving signals from
+// trigger. The first signal runs fn immediately. Signals received during the
+// interval after a call to fn are dropped. The next signal received after
+// the interval has passed runs fn again.
+//
+// Throttle returns if the context is cancelled, or if fn returns an error. It
+// returns nil once trigger is closed.
+func Throttle(ctx canceler, interval time.Duration, trigger <-chan struct{}, fn func() error) error {
+	done := ctx.Done()
+	for {
+		select {
+		case <-done:
+			return ctx.Err()
+		default:
+		}
+
+		select {
+		case <-done:
+			return ctx.Err()
+		case _, ok := <-trigger:
+			if !ok {
+				return nil
+			}
+			if err := fn(); err != nil {
+				return err
+			}
+		}
+
+		if err := throttleCooldown(ctx, interval, trigger); err != nil {
+			return err
+		}
+	}
+}
+
+// throttleCooldown waits for the interval to pass, dropping all signals
+// received from trigger in the meantime.
+func throttleCooldown(ctx canceler, interval time.Duration, trigger <-chan struct{}) error {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-trigger:
+			if !ok {
+				// wait for the interval without receiving from the closed channel.
+				// Throttle returns on the next receive.
+				trigger = nil
+			}
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
 // RetryUntil executes fn periodically until the function no longer returns an error, or
 // the timeout has elapsed, or the context is canceled. If the timeout has elapsed and
 // fn still returns an error, RetryUntil wraps the original error from fn and returns it.
@@ -108,18 +301,85 @@ func Periodic(ctx canceler, period time.Duration, fn func() error) error {
 //         fmt.Println("good things come to those who wait")
 //     }
 func RetryUntil(ctx canceler, timeout, period time.Duration, fn func(canceler) error) error {
+	attempts, err := retryUntil(ctx, timeout, constBackoff(period), nil, fn)
+	if attempts == 0 {
+		// fn was never run. Keep reporting success for backwards compatibility.
+		return nil
+	}
+	return err
+}
+
+// RetryUntilN behaves like RetryUntil, but additionally reports the number of
+// attempts made. If the context has been cancelled before fn was run the first
+// time, RetryUntilN returns 0 and the context's error.
+func RetryUntilN(ctx canceler, timeout, period time.Duration, fn func(canceler) error) (attempts int, err error) {
+	return retryUntil(ctx, timeout, constBackoff(period), nil, fn)
+}
+
+// RetryUntilRetryable behaves like RetryUntil, but stops retrying if fn
+// returns an error that is not retryable. The retryable function classifies
+// errors returned by fn. If retryable returns false, RetryUntilRetryable
+// returns the error from fn as is, without waiting for the timeout to elapse.
+// If retryable is nil, all errors are retried. Unlike RetryUntil, the
+// context's error is returned if the context has been cancelled before fn was
+// run the first time.
+//
+// Example:
+//
+//	err := RetryUntilRetryable(ctx, 1 * time.Minute, 1 * time.Second,
+//	    func(err error) bool { return !errors.Is(err, ErrNotFound) },
+//	    func(ctx canceler) error { return fetch(ctx) },
+//	)
+func RetryUntilRetryable(ctx canceler, timeout, period time.Duration, retryable func(error) bool, fn func(canceler) error) error {
+	_, err := retryUntil(ctx, timeout, constBackoff(period), retryable, fn)
+	return err
+}
+
+// RetryUntilBackoff behaves like RetryUntil, but the delay between attempts is
+// computed by backoff. The attempt counter passed to backoff starts with 1
+// after the first failed attempt and is incremented on every retry. The wait
+// between attempts is interrupted if the context is cancelled or the timeout
+// has elapsed. Unlike RetryUntil, the context's error is returned if the
+// context has been cancelled before fn was run the first time.
+//
+// Example:
+//
+//	err := RetryUntilBackoff(ctx, 1 * time.Minute,
+//	    func(attempt int) time.Duration { return time.Duration(attempt) * 100 * time.Millisecond },
+//	    func(ctx canceler) error { return connect(ctx) },
+//	)
+func RetryUntilBackoff(ctx canceler, timeout time.Duration, backoff func(attempt int) time.Duration, fn func(canceler) error) error {
+	_, err := retryUntil(ctx, timeout, backoff, nil, fn)
+	return err
+}
+
+func constBackoff(period time.Duration) func(int) time.Duration {
+	return func(_ int) time.Duration { return period }
+}
+
+// retryUntil runs the retry loop and reports the number of times fn has been
+// called. If fn has not been run at all, the context's error is returned.
+func retryUntil(ctx canceler, timeout time.Duration, backoff func(attempt int) time.Duration, retryable func(error) bool, fn func(canceler) error) (int, error) {
 	ctx, cancel := context.WithTimeout(ctxtool.FromCanceller(ctx), timeout)
 	defer cancel()
 
-	for ctx.Err() == nil {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	for attempts := 1; ; attempts++ {
 		checkErr := fn(ctx)
 		if checkErr == nil {
-			break
+			return attempts, nil
+		}
+		if retryable != nil && !retryable(checkErr) {
+			return attempts, checkErr
 		}
 
-		if err := Wait(ctx, period); err != nil {
-			return fmt.Errorf("the function has exceeded the deadline: %w", checkErr)
+		// The timeout might also elapse after fn has returned, while Wait has
+		// already finished. Always report the last error in that case.
+		if err := Wait(ctx, backoff(attempts)); err != nil || ctx.Err() != nil {
+			return attempts, fmt.Errorf("the function has exceeded the deadline: %w", checkErr)
 		}
 	}
-	return nil
 }
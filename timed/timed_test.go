@@ -20,6 +20,7 @@ package timed
 import (
 	"context"
 	"errors"
+	"math/rand"
 	"testing"
 	"time"
 
@@ -59,6 +60,34 @@ func TestWait(t *testing.T) {
 	})
 }
 
+func TestWaitRemaining(t *testing.T) {
+	t.Run("returns 0 after the given period", func(t *testing.T) {
+		remaining, err := WaitRemaining(context.Background(), 10*time.Millisecond)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), remaining)
+	})
+
+	t.Run("returns full duration on already cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		remaining, err := WaitRemaining(ctx, 1*time.Hour)
+		assert.Equal(t, context.Canceled, err)
+		assert.Greater(t, int64(remaining), int64(59*time.Minute))
+		assert.LessOrEqual(t, int64(remaining), int64(1*time.Hour))
+	})
+
+	t.Run("returns remaining duration if cancelled in the meantime", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		remaining, err := WaitRemaining(ctx, 1*time.Hour)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Greater(t, int64(remaining), int64(0))
+		assert.Less(t, int64(remaining), int64(1*time.Hour-50*time.Millisecond))
+	})
+}
+
 func TestPeriodic(t *testing.T) {
 	t.Run("run until cancel", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.TODO())
@@ -109,6 +138,273 @@ func TestPeriodic(t *testing.T) {
 	})
 }
 
+func TestPeriodicImmediate(t *testing.T) {
+	t.Run("run immediately", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		start := time.Now()
+		var first time.Duration
+		count := 0
+		PeriodicImmediate(ctx, 1*time.Hour, func() error {
+			first = time.Since(start)
+			count++
+			cancel()
+			return nil
+		})
+
+		assert.Equal(t, 1, count)
+		assert.Less(t, int64(first), int64(1*time.Hour))
+	})
+
+	t.Run("run periodically after first run", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		count := 0
+		const limit = 3
+		PeriodicImmediate(ctx, 10*time.Millisecond, func() error {
+			count++
+			if count == limit {
+				cancel()
+			}
+			return nil
+		})
+		assert.Equal(t, limit, count)
+	})
+
+	t.Run("do not run if context is already cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		count := 0
+		err := PeriodicImmediate(ctx, 100*time.Millisecond, func() error {
+			count++
+			return nil
+		})
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("return error of immediate run", func(t *testing.T) {
+		testErr := errors.New("test error")
+		count := 0
+		err := PeriodicImmediate(context.TODO(), 1*time.Hour, func() error {
+			count++
+			return testErr
+		})
+		assert.Equal(t, testErr, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestPeriodicJitter(t *testing.T) {
+	t.Run("run until cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		count := 0
+		const limit = 3
+		PeriodicJitter(ctx, 10*time.Millisecond, 5*time.Millisecond, func() error {
+			count++
+			if count == limit {
+				cancel()
+			}
+			return nil
+		})
+		assert.Equal(t, limit, count)
+	})
+
+	t.Run("do not run if context is already cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		count := 0
+		err := PeriodicJitter(ctx, time.Millisecond, time.Millisecond, func() error {
+			count++
+			return nil
+		})
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("return function error", func(t *testing.T) {
+		testErr := errors.New("test error")
+		err := PeriodicJitter(context.TODO(), 10*time.Millisecond, 5*time.Millisecond, func() error { return testErr })
+		assert.Equal(t, testErr, err)
+	})
+
+	t.Run("jitter stays within bounds", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(0))
+		period, jitter := 100*time.Millisecond, 10*time.Millisecond
+		for i := 0; i < 1000; i++ {
+			d := jitterDuration(rng, period, jitter)
+			assert.True(t, period-jitter <= d && d <= period+jitter, "duration %v out of bounds", d)
+		}
+	})
+
+	t.Run("jitter never produces non-positive durations", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(0))
+		for i := 0; i < 1000; i++ {
+			d := jitterDuration(rng, time.Millisecond, time.Hour)
+			assert.True(t, d > 0, "non-positive duration %v", d)
+		}
+	})
+}
+
+func TestDebounce(t *testing.T) {
+	t.Run("rapid triggers are collapsed into one call", func(t *testing.T) {
+		trigger := make(chan struct{})
+		go func() {
+			for i := 0; i < 10; i++ {
+				trigger <- struct{}{}
+				time.Sleep(time.Millisecond)
+			}
+			close(trigger)
+		}()
+
+		count := 0
+		err := Debounce(context.Background(), 50*time.Millisecond, trigger, func() error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("quiet periods separate calls", func(t *testing.T) {
+		trigger := make(chan struct{})
+		go func() {
+			for i := 0; i < 2; i++ {
+				trigger <- struct{}{}
+				trigger <- struct{}{}
+				time.Sleep(50 * time.Millisecond)
+			}
+			close(trigger)
+		}()
+
+		count := 0
+		err := Debounce(context.Background(), 10*time.Millisecond, trigger, func() error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("no call without trigger", func(t *testing.T) {
+		trigger := make(chan struct{})
+		close(trigger)
+
+		count := 0
+		err := Debounce(context.Background(), time.Millisecond, trigger, func() error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("return on cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		trigger := make(chan struct{}, 1)
+		trigger <- struct{}{}
+		cancel()
+
+		count := 0
+		err := Debounce(ctx, time.Millisecond, trigger, func() error {
+			count++
+			return nil
+		})
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("return function error", func(t *testing.T) {
+		testErr := errors.New("test error")
+		trigger := make(chan struct{}, 1)
+		trigger <- struct{}{}
+
+		err := Debounce(context.Background(), time.Millisecond, trigger, func() error { return testErr })
+		assert.Equal(t, testErr, err)
+	})
+}
+
+func TestThrottle(t *testing.T) {
+	t.Run("first trigger runs immediately", func(t *testing.T) {
+		trigger := make(chan struct{}, 1)
+		trigger <- struct{}{}
+
+		start := time.Now()
+		var first time.Duration
+		testErr := errors.New("stop")
+		err := Throttle(context.Background(), time.Hour, trigger, func() error {
+			first = time.Since(start)
+			return testErr
+		})
+		assert.Equal(t, testErr, err)
+		assert.Less(t, int64(first), int64(time.Hour))
+	})
+
+	t.Run("trigger during cooldown is dropped", func(t *testing.T) {
+		trigger := make(chan struct{})
+		go func() {
+			for i := 0; i < 5; i++ {
+				trigger <- struct{}{}
+			}
+			close(trigger)
+		}()
+
+		count := 0
+		err := Throttle(context.Background(), 50*time.Millisecond, trigger, func() error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("trigger after cooldown runs again", func(t *testing.T) {
+		trigger := make(chan struct{})
+		go func() {
+			for i := 0; i < 3; i++ {
+				trigger <- struct{}{}
+				time.Sleep(30 * time.Millisecond)
+			}
+			close(trigger)
+		}()
+
+		count := 0
+		err := Throttle(context.Background(), 10*time.Millisecond, trigger, func() error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("return on cancel during cooldown", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		trigger := make(chan struct{}, 1)
+		trigger <- struct{}{}
+
+		err := Throttle(ctx, time.Hour, trigger, func() error {
+			cancel()
+			return nil
+		})
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("return function error", func(t *testing.T) {
+		testErr := errors.New("test error")
+		trigger := make(chan struct{}, 1)
+		trigger <- struct{}{}
+
+		err := Throttle(context.Background(), time.Millisecond, trigger, func() error { return testErr })
+		assert.Equal(t, testErr, err)
+	})
+}
+
 func TestRetryUntil(t *testing.T) {
 	short := 50 * time.Millisecond
 	forever := 1 * time.Hour
@@ -131,4 +427,185 @@ func TestRetryUntil(t *testing.T) {
 		err := RetryUntil(ctx, forever, forever, alwaysError)
 		assert.NoError(t, err)
 	})
+
+	t.Run("retryuntil returns nil if fn was never run", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		for name, test := range map[string]struct {
+			ctx     context.Context
+			timeout time.Duration
+		}{
+			"canceled context": {ctx, forever},
+			"zero timeout":     {context.Background(), 0},
+		} {
+			t.Run(name, func(t *testing.T) {
+				count := 0
+				err := RetryUntil(test.ctx, test.timeout, forever, func(_ canceler) error {
+					count++
+					return errors.New("oops")
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, 0, count)
+			})
+		}
+	})
+}
+
+func TestRetryUntilN(t *testing.T) {
+	short := 50 * time.Millisecond
+	forever := 1 * time.Hour
+
+	t.Run("immediate success takes 1 attempt", func(t *testing.T) {
+		attempts, err := RetryUntilN(context.Background(), forever, forever, func(_ canceler) error { return nil })
+		assert.NoError(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("reports attempts until success", func(t *testing.T) {
+		count := 0
+		attempts, err := RetryUntilN(context.Background(), forever, time.Millisecond, func(_ canceler) error {
+			if count++; count < 3 {
+				return errors.New("oops")
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("reports attempts on timeout", func(t *testing.T) {
+		count := 0
+		attempts, err := RetryUntilN(context.Background(), short, time.Millisecond, func(_ canceler) error {
+			count++
+			return errors.New("oops")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, count, attempts)
+		assert.Greater(t, attempts, 1)
+	})
+
+	t.Run("no attempt if context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		attempts, err := RetryUntilN(ctx, forever, forever, func(_ canceler) error { return nil })
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 0, attempts)
+	})
+}
+
+func TestRetryUntilRetryable(t *testing.T) {
+	short := 50 * time.Millisecond
+	forever := 1 * time.Hour
+	errPermanent := errors.New("permanent")
+	errTemporary := errors.New("temporary")
+	isRetryable := func(err error) bool { return !errors.Is(err, errPermanent) }
+
+	t.Run("returns permanent error immediately", func(t *testing.T) {
+		count := 0
+		err := RetryUntilRetryable(context.Background(), forever, forever, isRetryable, func(_ canceler) error {
+			count++
+			return errPermanent
+		})
+		assert.Equal(t, errPermanent, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("retries temporary errors until deadline", func(t *testing.T) {
+		count := 0
+		err := RetryUntilRetryable(context.Background(), short, time.Millisecond, isRetryable, func(_ canceler) error {
+			count++
+			return errTemporary
+		})
+		assert.True(t, errors.Is(err, errTemporary))
+		assert.NotEqual(t, errTemporary, err, "expected deadline error wrapping the last error")
+		assert.Greater(t, count, 1)
+	})
+
+	t.Run("stops retrying once error becomes permanent", func(t *testing.T) {
+		count := 0
+		err := RetryUntilRetryable(context.Background(), forever, time.Millisecond, isRetryable, func(_ canceler) error {
+			count++
+			if count < 3 {
+				return errTemporary
+			}
+			return errPermanent
+		})
+		assert.Equal(t, errPermanent, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("nil retryable retries everything", func(t *testing.T) {
+		err := RetryUntilRetryable(context.Background(), short, time.Millisecond, nil, func(_ canceler) error {
+			return errPermanent
+		})
+		assert.True(t, errors.Is(err, errPermanent))
+		assert.NotEqual(t, errPermanent, err)
+	})
+
+	t.Run("does not run fn if context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		count := 0
+		err := RetryUntilRetryable(ctx, forever, forever, nil, func(_ canceler) error {
+			count++
+			return nil
+		})
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
+func TestRetryUntilBackoff(t *testing.T) {
+	forever := 1 * time.Hour
+
+	t.Run("delay between attempts grows with backoff", func(t *testing.T) {
+		var attempts []int
+		var starts []time.Time
+		backoff := func(attempt int) time.Duration {
+			attempts = append(attempts, attempt)
+			return time.Duration(attempt) * 20 * time.Millisecond
+		}
+
+		err := RetryUntilBackoff(context.Background(), forever, backoff, func(_ canceler) error {
+			starts = append(starts, time.Now())
+			if len(starts) < 4 {
+				return errors.New("oops")
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, attempts)
+
+		if assert.Len(t, starts, 4) {
+			for i := 1; i < len(starts); i++ {
+				gap := starts[i].Sub(starts[i-1])
+				assert.GreaterOrEqual(t, int64(gap), int64(time.Duration(i)*20*time.Millisecond))
+			}
+		}
+	})
+
+	t.Run("returns last error if deadline is exceeded during backoff", func(t *testing.T) {
+		errFail := errors.New("oops")
+		err := RetryUntilBackoff(context.Background(), 50*time.Millisecond,
+			func(_ int) time.Duration { return forever },
+			func(_ canceler) error { return errFail },
+		)
+		assert.True(t, errors.Is(err, errFail))
+		assert.NotEqual(t, errFail, err, "expected deadline error wrapping the last error")
+	})
+
+	t.Run("does not retry if context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		count := 0
+		err := RetryUntilBackoff(ctx, forever, func(_ int) time.Duration { return forever }, func(_ canceler) error {
+			count++
+			return errors.New("oops")
+		})
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 0, count)
+	})
 }
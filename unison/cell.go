@@ -17,7 +17,19 @@
 
 package unison
 
-import "sync"
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCellClosed is returned by Wait if the Cell has been closed and all
+// updates have been consumed.
+var ErrCellClosed = errors.New("cell closed")
+
+// errCellWaitInterrupted is used internally to signal that a wait operation
+// got interrupted by cancellation or timeout.
+var errCellWaitInterrupted = errors.New("cell wait interrupted")
 
 // Cell stores some state of type interface{}.
 // Intermittent updates are lost, in case the Cell is updated faster than the
@@ -40,6 +52,8 @@ type Cell struct {
 	// logical config state update counters.
 	// The readID always follows writeID. We are using the most recent state
 	// update if readID == waitID.
+	// Each CellReader maintains its own readID, such that readers do not
+	// advance each others cursor.
 	writeID uint64
 	readID  uint64
 
@@ -60,6 +74,25 @@ type Cell struct {
 	// We use fine grained locking. If `waiterSessionID` is increased since our last lock attempt, then our
 	// current wait session is 'outdated' (numWaiter, waiter must not be modified).
 	waiterSessionID uint
+
+	// closed is set by Close. Waiting go-routines are unblocked and further
+	// calls to Wait fail with ErrCellClosed once all updates have been read.
+	closed bool
+
+	// optional hook to replace the state after it has been read. onRead is
+	// called with c.mu being locked. Used by ReducingCell.
+	onRead func(st interface{}) interface{}
+}
+
+// CellReader reads the state of a Cell using its own read cursor. A
+// CellReader is created via (*Cell).Subscribe. Intermediate updates might
+// still be lost for each reader, if the Cell is updated faster than the
+// reader consumes the updates.
+type CellReader struct {
+	cell *Cell
+
+	// read cursor of the reader. Guarded by cell.mu.
+	readID uint64
 }
 
 // NewCell creates a new call instance with its initial state. Subsequent reads
@@ -72,19 +105,58 @@ func NewCell(st interface{}) *Cell {
 func (c *Cell) Get() interface{} {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return c.read()
+	return c.read(&c.readID)
 }
 
 // Wait blocks until it an update since the last call to Get or Wait has been found.
 // The cancel context can be used to interrupt the call to Wait early. The
 // error value will be set to the value returned by cancel.Err() in case Wait
-// was interrupted. Wait does not produce any errors that need to be handled by itself.
+// was interrupted. If the Cell has been closed and the last update has
+// already been read, Wait returns ErrCellClosed.
 func (c *Cell) Wait(cancel Canceler) (interface{}, error) {
+	return c.waitCancel(&c.readID, cancel)
+}
+
+// WaitTimeout blocks until an update since the last call to Get or Wait has
+// been found, or the timeout duration has passed. WaitTimeout returns false if
+// the timeout was reached before an update has been found.
+// Unlike Wait with a context.WithTimeout, WaitTimeout does not require a new
+// context to be allocated for each call.
+// WaitTimeout returns false immediately if the Cell has been closed and the
+// last update has already been read.
+func (c *Cell) WaitTimeout(duration time.Duration) (interface{}, bool) {
+	return c.waitTimeout(&c.readID, duration)
+}
+
+func (c *Cell) waitCancel(readID *uint64, cancel Canceler) (interface{}, error) {
+	st, err := c.wait(readID, cancel.Done(), nil)
+	if err == errCellWaitInterrupted {
+		return nil, cancel.Err()
+	}
+	return st, err
+}
+
+func (c *Cell) waitTimeout(readID *uint64, duration time.Duration) (interface{}, bool) {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	st, err := c.wait(readID, nil, timer.C)
+	return st, err == nil
+}
+
+// wait blocks until an update since the last read using the readID cursor is
+// available, done is closed, or timeout fires. wait returns
+// errCellWaitInterrupted if it was interrupted, and ErrCellClosed if the Cell
+// has been closed. done or timeout can be nil.
+func (c *Cell) wait(readID *uint64, done <-chan struct{}, timeout <-chan time.Time) (interface{}, error) {
 	c.mu.Lock()
 
-	if c.readID != c.writeID {
+	if *readID != c.writeID {
 		defer c.mu.Unlock()
-		return c.read(), nil
+		return c.read(readID), nil
+	}
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrCellClosed
 	}
 
 	var waiter chan struct{}
@@ -110,38 +182,89 @@ func (c *Cell) Wait(cancel Canceler) (interface{}, error) {
 	c.mu.Unlock()
 
 	select {
-	case <-cancel.Done():
-		// we don't bother to check the waiter channel again. Cancellation if
-		// detected has priority.
+	case <-waiter:
 		c.mu.Lock()
 		defer c.mu.Unlock()
 
-		// if waiterID and c.waiterID do not match we have had a race with `Set`
-		// cleaning up the waiter state and another go-routine already calling wait
-		// before we managed to lock the mutex.  In that case our waiterSession has
-		// already been expired and we must not attempt to clean up the current
-		// waiter state.
-		if c.waiterSessionID == waiterSession {
-			c.numWaiter--
-			if c.numWaiter < 0 {
-				// Race between Set and context cancellation. Set did already clean up the overall waiter state.
-				// We must not attempt to clean up the state again -> repair state by undoing the local cleanup
-				c.numWaiter++
-			} else if c.numWaiter == 0 {
-				// No more go-routine waiting for a state update and Set did not trigger yet. Let's clean up.
-				c.waiterBuf = c.waiter
-				c.waiter = nil
-			}
+		// waiter resource has been cleaned up by `Set` or `Close`. Just read and
+		// return the current known state, if there was an update.
+		if *readID == c.writeID && c.closed {
+			return nil, ErrCellClosed
 		}
-		return nil, cancel.Err()
-	case <-waiter:
-		c.mu.Lock()
-		defer c.mu.Unlock()
+		return c.read(readID), nil
+	case <-done:
+	case <-timeout:
+	}
+
+	// we don't bother to check the waiter channel again. Cancellation if
+	// detected has priority.
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-		// waiter resource has been cleaned up by `Set`. Just read and return the
-		// current known state.
-		return c.read(), nil
+	// if waiterID and c.waiterID do not match we have had a race with `Set`
+	// cleaning up the waiter state and another go-routine already calling wait
+	// before we managed to lock the mutex.  In that case our waiterSession has
+	// already been expired and we must not attempt to clean up the current
+	// waiter state.
+	if c.waiterSessionID == waiterSession {
+		c.numWaiter--
+		if c.numWaiter < 0 {
+			// Race between Set and context cancellation. Set did already clean up the overall waiter state.
+			// We must not attempt to clean up the state again -> repair state by undoing the local cleanup
+			c.numWaiter++
+		} else if c.numWaiter == 0 {
+			// No more go-routine waiting for a state update and Set did not trigger yet. Let's clean up.
+			c.waiterBuf = c.waiter
+			c.waiter = nil
+		}
 	}
+	return nil, errCellWaitInterrupted
+}
+
+// Subscribe creates a new CellReader with its own read cursor. Reads via the
+// CellReader do not influence other readers, or reads via Get and Wait on the
+// Cell itself. The reader only observes updates that happen after Subscribe
+// has been called.
+func (c *Cell) Subscribe() *CellReader {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &CellReader{cell: c, readID: c.writeID}
+}
+
+// Observe returns a channel that receives the state updates of the Cell. Like
+// with Wait, intermediate updates might be lost if the consumer is slower than
+// the producer. Observe uses its own read cursor, like a CellReader created via
+// Subscribe, and only reports updates that happen after Observe has been
+// called.
+//
+// Observe starts a go-routine that forwards updates to the channel. The
+// go-routine returns and the channel is closed once cancel signals
+// cancellation or the Cell is closed.
+//
+// Example:
+//
+//	for st := range cell.Observe(ctx) {
+//		...
+//	}
+func (c *Cell) Observe(cancel Canceler) <-chan interface{} {
+	reader := c.Subscribe()
+	ch := make(chan interface{})
+	go func() {
+		defer close(ch)
+		for {
+			st, err := reader.Wait(cancel)
+			if err != nil {
+				return
+			}
+
+			select {
+			case ch <- st:
+			case <-cancel.Done():
+				return
+			}
+		}
+	}()
+	return ch
 }
 
 // Set updates the state of the Cell and unblocks a waiting consumer.
@@ -149,7 +272,63 @@ func (c *Cell) Wait(cancel Canceler) (interface{}, error) {
 func (c *Cell) Set(st interface{}) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.write(st)
+}
 
+// Update computes the new state from the current state and unblocks a waiting
+// consumer. The current state is read, passed to fn, and the result is stored
+// atomically, such that concurrent updates are not lost.
+// Update does not block, besides waiting for other producers.
+//
+// fn is run while the Cell is locked. fn must not call back into the Cell, or
+// Update will deadlock.
+func (c *Cell) Update(fn func(old interface{}) interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.write(fn(c.state))
+}
+
+// Close closes the Cell and unblocks all waiting consumers. Similar to
+// closing a channel, consumers will still receive the last update if it has
+// not been read yet. Afterwards Wait returns ErrCellClosed immediately. Get
+// continues to return the current state. Calling Close multiple times is
+// safe.
+func (c *Cell) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+
+	c.closed = true
+	if c.waiter != nil {
+		close(c.waiter)
+		c.waiter = nil
+		c.numWaiter = 0
+	}
+}
+
+// CompareAndSet updates the state of the Cell to new, if the current state is
+// equal to old. Waiting consumers are unblocked on success. CompareAndSet
+// reports whether the state has been updated.
+//
+// The states are compared using Go equality (==). CompareAndSet panics if the
+// current state and old have the same non-comparable type, e.g. a slice or a
+// map.
+func (c *Cell) CompareAndSet(old, new interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state != old {
+		return false
+	}
+	c.write(new)
+	return true
+}
+
+// write updates the state and notifies waiting go-routines.
+//
+// IMPORTANT: c.mu MUST be locked while calling write.
+func (c *Cell) write(st interface{}) {
 	c.writeID++
 	c.state = st
 
@@ -160,11 +339,40 @@ func (c *Cell) Set(st interface{}) {
 	}
 }
 
-// read returns the current state and ensures that the next wait operation will
-// only block correctly if there was no Set since the last read.
+// Get returns the current state.
+func (r *CellReader) Get() interface{} {
+	r.cell.mu.Lock()
+	defer r.cell.mu.Unlock()
+	return r.cell.read(&r.readID)
+}
+
+// Wait blocks until an update since the last call to Get or Wait on the
+// reader has been found. The cancel context can be used to interrupt the call
+// to Wait early. The error value will be set to the value returned by
+// cancel.Err() in case Wait was interrupted. If the Cell has been closed and
+// the reader has already read the last update, Wait returns ErrCellClosed.
+func (r *CellReader) Wait(cancel Canceler) (interface{}, error) {
+	return r.cell.waitCancel(&r.readID, cancel)
+}
+
+// WaitTimeout blocks until an update since the last call to Get or Wait on
+// the reader has been found, or the timeout duration has passed. WaitTimeout
+// returns false if the timeout was reached before an update has been found,
+// or if the Cell has been closed.
+func (r *CellReader) WaitTimeout(duration time.Duration) (interface{}, bool) {
+	return r.cell.waitTimeout(&r.readID, duration)
+}
+
+// read returns the current state and ensures that the next wait operation
+// using the readID cursor will only block correctly if there was no Set since
+// the last read.
 //
 // IMPORTANT: c.mu MUST be locked while calling read.
-func (c *Cell) read() interface{} {
-	c.readID = c.writeID
-	return c.state
+func (c *Cell) read(readID *uint64) interface{} {
+	*readID = c.writeID
+	st := c.state
+	if c.onRead != nil {
+		c.state = c.onRead(st)
+	}
+	return st
 }
@@ -0,0 +1,66 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package unison
+
+// TypedCell stores some state of type T. TypedCell wraps a Cell and provides
+// the same update semantics, but removes the need for type assertions when
+// reading the state.
+//
+// The zero value of TypedCell is valid and holds the zero value of T, but a
+// value of type TypedCell can not be copied.
+type TypedCell[T any] struct {
+	cell Cell
+}
+
+// NewTypedCell creates a new typed cell instance with its initial state.
+// Subsequent reads will return this state, if there have been no updates.
+func NewTypedCell[T any](st T) *TypedCell[T] {
+	return &TypedCell[T]{cell: Cell{state: st}}
+}
+
+// Get returns the current state.
+func (c *TypedCell[T]) Get() T {
+	return typedState[T](c.cell.Get())
+}
+
+// Wait blocks until it an update since the last call to Get or Wait has been found.
+// The cancel context can be used to interrupt the call to Wait early. The
+// error value will be set to the value returned by cancel.Err() in case Wait
+// was interrupted. The zero value of T is returned if Wait was interrupted.
+func (c *TypedCell[T]) Wait(cancel Canceler) (T, error) {
+	st, err := c.cell.Wait(cancel)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return typedState[T](st), nil
+}
+
+// Set updates the state of the TypedCell and unblocks a waiting consumer.
+// Set does not block.
+func (c *TypedCell[T]) Set(st T) {
+	c.cell.Set(st)
+}
+
+// typedState converts the untyped state of a Cell to T. The state of the
+// underlying Cell is nil if the TypedCell has not been initialized, in which
+// case the zero value of T is returned.
+func typedState[T any](st interface{}) T {
+	v, _ := st.(T)
+	return v
+}
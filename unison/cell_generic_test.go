@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package unison
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedCell(t *testing.T) {
+	t.Run("zero value returns zero state", func(t *testing.T) {
+		var cell TypedCell[uint]
+		assert.Equal(t, uint(0), cell.Get())
+	})
+
+	t.Run("read state from init", func(t *testing.T) {
+		cell := NewTypedCell[uint](1)
+		assert.Equal(t, uint(1), cell.Get())
+	})
+
+	t.Run("sync update cell", func(t *testing.T) {
+		cell := NewTypedCell[uint](1)
+		cell.Set(2)
+		assert.Equal(t, uint(2), cell.Get())
+	})
+
+	t.Run("Wait does not block after set", func(t *testing.T) {
+		cell := NewTypedCell[uint](1)
+		cell.Set(2)
+
+		val, err := cell.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), val)
+	})
+
+	t.Run("cancel wait", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		cell := NewTypedCell[uint](1)
+		val, err := cell.Wait(ctx)
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, uint(0), val)
+	})
+
+	t.Run("wait for update", func(t *testing.T) {
+		cell := NewTypedCell[uint](1)
+
+		var tg TaskGroup
+		defer tg.Stop()
+		tg.Go(func(_ context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			cell.Set(2)
+			return nil
+		})
+
+		val, err := cell.Wait(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), val)
+	})
+}
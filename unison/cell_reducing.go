@@ -0,0 +1,87 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package unison
+
+import "time"
+
+// ReducingCell is a Cell that accumulates deltas into its state. Producers
+// report deltas via Add, which are merged into the current state by a user
+// provided reduce function. Consumers read the accumulated state via Get or
+// Wait, without the producer having to compute the absolute state.
+//
+// By default the state is cumulative: reads return the accumulation of all
+// deltas since the cell was created. If ResetOnRead is set, the state is reset
+// to the initial state after each read, such that reads return the
+// accumulation of all deltas since the last read.
+//
+// A ReducingCell must be created with NewReducingCell, and can not be copied.
+type ReducingCell struct {
+	// ResetOnRead configures the cell to reset the state to the initial state
+	// after it has been read by Get or Wait. ResetOnRead must not be modified
+	// after the cell has been used.
+	ResetOnRead bool
+
+	cell    Cell
+	initial interface{}
+	reduce  func(acc, delta interface{}) interface{}
+}
+
+// NewReducingCell creates a new ReducingCell. The reduce function is used to
+// merge a delta into the current accumulated state. Reduce is run while the
+// cell is locked, and must not call back into the cell.
+func NewReducingCell(initial interface{}, reduce func(acc, delta interface{}) interface{}) *ReducingCell {
+	r := &ReducingCell{initial: initial, reduce: reduce}
+	r.cell.state = initial
+	r.cell.onRead = r.afterRead
+	return r
+}
+
+// Add merges the delta into the current state and unblocks a waiting consumer.
+// Add does not block.
+func (r *ReducingCell) Add(delta interface{}) {
+	r.cell.Update(func(acc interface{}) interface{} {
+		return r.reduce(acc, delta)
+	})
+}
+
+// Get returns the current accumulated state.
+func (r *ReducingCell) Get() interface{} {
+	return r.cell.Get()
+}
+
+// Wait blocks until a delta has been added since the last call to Get or Wait.
+// The cancel context can be used to interrupt the call to Wait early. The
+// error value will be set to the value returned by cancel.Err() in case Wait
+// was interrupted.
+func (r *ReducingCell) Wait(cancel Canceler) (interface{}, error) {
+	return r.cell.Wait(cancel)
+}
+
+// WaitTimeout blocks until a delta has been added since the last call to Get
+// or Wait, or the timeout duration has passed. WaitTimeout returns false if
+// the timeout was reached before a delta has been added.
+func (r *ReducingCell) WaitTimeout(duration time.Duration) (interface{}, bool) {
+	return r.cell.WaitTimeout(duration)
+}
+
+func (r *ReducingCell) afterRead(st interface{}) interface{} {
+	if r.ResetOnRead {
+		return r.initial
+	}
+	return st
+}
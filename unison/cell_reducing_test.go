@@ -0,0 +1,113 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package unison
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReducingCell(t *testing.T) {
+	sum := func(acc, delta interface{}) interface{} {
+		return acc.(int) + delta.(int)
+	}
+
+	t.Run("read state from init", func(t *testing.T) {
+		cell := NewReducingCell(0, sum)
+		assert.Equal(t, 0, cell.Get())
+	})
+
+	t.Run("cumulative state", func(t *testing.T) {
+		cell := NewReducingCell(0, sum)
+		cell.Add(1)
+		cell.Add(2)
+		assert.Equal(t, 3, cell.Get())
+
+		cell.Add(3)
+		val, err := cell.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, 6, val)
+	})
+
+	t.Run("reset on read", func(t *testing.T) {
+		cell := NewReducingCell(0, sum)
+		cell.ResetOnRead = true
+		cell.Add(1)
+		cell.Add(2)
+
+		val, err := cell.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, 3, val)
+		assert.Equal(t, 0, cell.Get())
+
+		cell.Add(3)
+		assert.Equal(t, 3, cell.Get())
+	})
+
+	t.Run("wait blocks until delta is added", func(t *testing.T) {
+		cell := NewReducingCell(0, sum)
+		cell.Get()
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		_, err := cell.Wait(ctx)
+		assert.Equal(t, context.Canceled, err)
+
+		cell.Add(1)
+		val, err := cell.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, val)
+	})
+}
+
+// ExampleReducingCell_acking tracks the number of ACKed events without
+// backpressure in the generating thread. Unlike ExampleCell_acking, the
+// producer reports deltas only.
+func ExampleReducingCell_acking() {
+	acked := NewReducingCell(uint(0), func(acc, delta interface{}) interface{} {
+		return acc.(uint) + delta.(uint)
+	})
+	acked.ResetOnRead = true
+
+	const max = 100
+
+	// start go-routine that ACKs single events
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	wg.Add(1)
+	go func() { // ACKer thread
+		defer wg.Done()
+		for send := 0; send < max; send++ {
+			acked.Add(uint(1))
+		}
+	}()
+
+	// reader loop
+	var totalACKed uint
+	for totalACKed < max {
+		st, _ := acked.Wait(context.TODO())
+		totalACKed += st.(uint)
+	}
+
+	fmt.Println("Total:", totalACKed)
+	// Output: Total: 100
+}
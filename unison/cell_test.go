@@ -25,6 +25,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
 )
 
 func TestCell(t *testing.T) {
@@ -96,6 +98,449 @@ func TestCell(t *testing.T) {
 	})
 }
 
+func TestCell_Update(t *testing.T) {
+	t.Run("update state", func(t *testing.T) {
+		cell := NewCell(1)
+		cell.Update(func(old interface{}) interface{} { return old.(int) + 1 })
+		assert.Equal(t, 2, cell.Get())
+	})
+
+	t.Run("Wait does not block after update", func(t *testing.T) {
+		cell := NewCell(1)
+		cell.Update(func(old interface{}) interface{} { return old.(int) + 1 })
+
+		val, err := cell.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, val)
+	})
+
+	t.Run("concurrent updates are not lost", func(t *testing.T) {
+		const workers = 8
+		const updates = 1000
+
+		cell := NewCell(0)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < updates; i++ {
+					cell.Update(func(old interface{}) interface{} { return old.(int) + 1 })
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, workers*updates, cell.Get())
+	})
+}
+
+func TestCell_CompareAndSet(t *testing.T) {
+	t.Run("swap if state matches", func(t *testing.T) {
+		cell := NewCell("init")
+		assert.True(t, cell.CompareAndSet("init", "running"))
+		assert.Equal(t, "running", cell.Get())
+	})
+
+	t.Run("no swap if state does not match", func(t *testing.T) {
+		cell := NewCell("init")
+		assert.False(t, cell.CompareAndSet("running", "stopped"))
+		assert.Equal(t, "init", cell.Get())
+	})
+
+	t.Run("no swap if types do not match", func(t *testing.T) {
+		cell := NewCell(1)
+		assert.False(t, cell.CompareAndSet(int64(1), 2))
+		assert.Equal(t, 1, cell.Get())
+	})
+
+	t.Run("Wait does not block after swap", func(t *testing.T) {
+		cell := NewCell("init")
+		cell.Get()
+		cell.CompareAndSet("init", "running")
+
+		val, err := cell.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, "running", val)
+	})
+
+	t.Run("Wait blocks after failed swap", func(t *testing.T) {
+		cell := NewCell("init")
+		cell.Get()
+		cell.CompareAndSet("running", "stopped")
+
+		_, ok := cell.WaitTimeout(10 * time.Millisecond)
+		assert.False(t, ok)
+	})
+
+	t.Run("panics on non-comparable states", func(t *testing.T) {
+		cell := NewCell([]int{1})
+		expectPanic(t, func() {
+			cell.CompareAndSet([]int{1}, []int{2})
+		})
+	})
+
+	t.Run("only one concurrent swap succeeds", func(t *testing.T) {
+		const workers = 8
+
+		cell := NewCell(0)
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		succeeded := 0
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				if cell.CompareAndSet(0, w+1) {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+				}
+			}(w)
+		}
+		wg.Wait()
+		assert.Equal(t, 1, succeeded)
+	})
+}
+
+func TestCell_WaitTimeout(t *testing.T) {
+	t.Run("does not block after set", func(t *testing.T) {
+		cell := NewCell("init")
+		cell.Set("test")
+
+		val, ok := cell.WaitTimeout(10 * time.Minute)
+		assert.True(t, ok)
+		assert.Equal(t, "test", val)
+	})
+
+	t.Run("timeout without update", func(t *testing.T) {
+		cell := NewCell("init")
+		val, ok := cell.WaitTimeout(10 * time.Millisecond)
+		assert.False(t, ok)
+		assert.Nil(t, val)
+	})
+
+	t.Run("wait for update", func(t *testing.T) {
+		cell := NewCell("init")
+
+		var tg TaskGroup
+		defer tg.Stop()
+		tg.Go(func(_ context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			cell.Set("updated")
+			return nil
+		})
+
+		val, ok := cell.WaitTimeout(10 * time.Minute)
+		assert.True(t, ok)
+		assert.Equal(t, "updated", val)
+	})
+
+	t.Run("update races with timeout", func(t *testing.T) {
+		cell := NewCell(0)
+		for i := 1; i <= 100; i++ {
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				time.Sleep(time.Millisecond)
+				cell.Set(i)
+			}()
+
+			val, ok := cell.WaitTimeout(time.Millisecond)
+			wg.Wait()
+			if !ok {
+				// the update must not be lost if the timer did fire first.
+				val, ok = cell.WaitTimeout(10 * time.Minute)
+			}
+			require.True(t, ok)
+			require.Equal(t, i, val)
+		}
+	})
+}
+
+func TestCell_Subscribe(t *testing.T) {
+	t.Run("reader observes updates after subscribe only", func(t *testing.T) {
+		cell := NewCell("init")
+		cell.Set("before")
+		reader := cell.Subscribe()
+
+		_, ok := reader.WaitTimeout(10 * time.Millisecond)
+		assert.False(t, ok)
+		assert.Equal(t, "before", reader.Get())
+	})
+
+	t.Run("readers do not advance each others cursor", func(t *testing.T) {
+		cell := NewCell("init")
+		r1, r2 := cell.Subscribe(), cell.Subscribe()
+		cell.Set("update")
+
+		val, err := r1.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, "update", val)
+
+		val, err = r2.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, "update", val)
+
+		// the cell itself has not been read yet either
+		val, err = cell.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, "update", val)
+
+		_, ok := r1.WaitTimeout(10 * time.Millisecond)
+		assert.False(t, ok)
+	})
+
+	t.Run("all readers are unblocked on update", func(t *testing.T) {
+		const readers = 4
+		cell := NewCell("init")
+
+		var wgStart, wgDone sync.WaitGroup
+		values := make([]interface{}, readers)
+		for i := 0; i < readers; i++ {
+			reader := cell.Subscribe()
+			wgStart.Add(1)
+			wgDone.Add(1)
+			go func(i int) {
+				defer wgDone.Done()
+				wgStart.Done()
+				values[i], _ = reader.Wait(context.TODO())
+			}(i)
+		}
+
+		wgStart.Wait()
+		cell.Set("update")
+		wgDone.Wait()
+		for _, val := range values {
+			assert.Equal(t, "update", val)
+		}
+	})
+}
+
+func TestCell_ConcurrentSetAndCancel(t *testing.T) {
+	// Stress the waiter pool: waiters are cancelled while a producer
+	// concurrently updates the cell. Run with -race to validate the
+	// waiterBuf reuse and the waiter session bookkeeping.
+	const producers = 4
+	const consumers = 8
+	const updates = 1000
+
+	cell := NewCell(0)
+
+	var wgProducer sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wgProducer.Add(1)
+		go func() {
+			defer wgProducer.Done()
+			for i := 1; i <= updates; i++ {
+				cell.Set(i)
+			}
+		}()
+	}
+
+	var wgConsumer sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		wgConsumer.Add(1)
+		go func() {
+			defer wgConsumer.Done()
+			for i := 0; i < updates; i++ {
+				ctx, cancel := context.WithCancel(context.Background())
+				if i%2 == 0 {
+					cancel()
+				}
+				go cancel()
+				cell.Wait(ctx)
+			}
+		}()
+	}
+
+	wgProducer.Wait()
+	wgConsumer.Wait()
+
+	// a final update must unblock a new waiter.
+	cell.Set(-1)
+	val, err := cell.Wait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, -1, val)
+}
+
+func TestCell_Close(t *testing.T) {
+	t.Run("waiters are released on close", func(t *testing.T) {
+		const waiters = 4
+
+		cell := NewCell("init")
+		cell.Get()
+
+		errs := make(chan error, waiters)
+		for i := 0; i < waiters; i++ {
+			go func() {
+				_, err := cell.Wait(context.Background())
+				errs <- err
+			}()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		cell.Close()
+		for i := 0; i < waiters; i++ {
+			assert.Equal(t, ErrCellClosed, <-errs)
+		}
+	})
+
+	t.Run("wait after close fails", func(t *testing.T) {
+		cell := NewCell("init")
+		cell.Close()
+
+		_, err := cell.Wait(context.Background())
+		assert.Equal(t, ErrCellClosed, err)
+
+		_, ok := cell.WaitTimeout(time.Hour)
+		assert.False(t, ok)
+	})
+
+	t.Run("get returns last state after close", func(t *testing.T) {
+		cell := NewCell("init")
+		cell.Set("last")
+		cell.Close()
+		assert.Equal(t, "last", cell.Get())
+	})
+
+	t.Run("unread update is returned before close error", func(t *testing.T) {
+		cell := NewCell("init")
+		cell.Set("last")
+		cell.Close()
+
+		val, err := cell.Wait(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "last", val)
+
+		_, err = cell.Wait(context.Background())
+		assert.Equal(t, ErrCellClosed, err)
+	})
+
+	t.Run("readers are released on close", func(t *testing.T) {
+		cell := NewCell("init")
+		reader := cell.Subscribe()
+
+		errs := make(chan error, 1)
+		go func() {
+			_, err := reader.Wait(context.Background())
+			errs <- err
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		cell.Close()
+		assert.Equal(t, ErrCellClosed, <-errs)
+
+		_, ok := reader.WaitTimeout(time.Hour)
+		assert.False(t, ok)
+	})
+
+	t.Run("close after cancelled wait", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		cell := NewCell("init")
+		cell.Get()
+		_, err := cell.Wait(ctx)
+		assert.Equal(t, context.Canceled, err)
+
+		cell.Close()
+		cell.Close() // must not panic
+		_, err = cell.Wait(context.Background())
+		assert.Equal(t, ErrCellClosed, err)
+	})
+}
+
+func TestCell_Observe(t *testing.T) {
+	t.Run("receive updates", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cell := NewCell(0)
+		ch := cell.Observe(ctx)
+		for i := 1; i <= 3; i++ {
+			cell.Set(i)
+			assert.Equal(t, i, <-ch)
+		}
+	})
+
+	t.Run("updates are coalesced", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cell := NewCell(0)
+		ch := cell.Observe(ctx)
+		cell.Set(1)
+		time.Sleep(10 * time.Millisecond)
+		cell.Set(2)
+		cell.Set(3)
+
+		var last interface{}
+		for last != 3 {
+			last = <-ch
+		}
+	})
+
+	t.Run("does not interfere with Wait", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cell := NewCell(0)
+		cell.Get()
+		ch := cell.Observe(ctx)
+		cell.Set(1)
+		assert.Equal(t, 1, <-ch)
+
+		val, err := cell.Wait(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, val)
+	})
+
+	t.Run("channel is closed on cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cell := NewCell(0)
+		ch := cell.Observe(ctx)
+		cancel()
+
+		for range ch {
+		}
+	})
+
+	t.Run("channel is closed on cancel with pending update", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cell := NewCell(0)
+		ch := cell.Observe(ctx)
+		cell.Set(1)
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		for range ch {
+		}
+	})
+
+	t.Run("channel is closed on cell close", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		cell := NewCell(0)
+		ch := cell.Observe(context.Background())
+		cell.Close()
+
+		for range ch {
+		}
+	})
+}
+
 // ExampleCellACK tracks the number of ACKed events without backpressure in the
 // generating thread, even if the consumer is blocked. The consumer computes
 func ExampleCell_acking() {
@@ -155,3 +600,99 @@ func ExampleCell_acking() {
 	fmt.Println("Total:", totalACKed)
 	// Output: Total: 100
 }
+
+// BenchmarkCell measures the allocations required by Wait. A Wait that is
+// cancelled reuses the waiter channel on the next call. A Wait that is
+// unblocked by Set requires a new waiter channel for the next wait session,
+// because Set broadcasts the update by closing the channel. The allocations
+// reported when cancellation races a concurrent Set are caused by creating the
+// context per wait, not by the Cell.
+func BenchmarkCell(b *testing.B) {
+	b.Run("set then wait", func(b *testing.B) {
+		cell := NewCell(0)
+		ctx := context.Background()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			cell.Set(i)
+			cell.Wait(ctx)
+		}
+	})
+
+	b.Run("wait for concurrent set", func(b *testing.B) {
+		cell := NewCell(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ack := make(chan struct{})
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ack:
+					cell.Set(nil)
+				}
+			}
+		}()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			ack <- struct{}{}
+			cell.Wait(ctx)
+		}
+	})
+
+	b.Run("cancelled wait", func(b *testing.B) {
+		cell := NewCell(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			cell.Wait(ctx)
+		}
+	})
+
+	b.Run("cancel races concurrent set", func(b *testing.B) {
+		cell := NewCell(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		setC := make(chan struct{})
+		cancelC := make(chan context.CancelFunc)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-setC:
+					cell.Set(nil)
+				}
+			}
+		}()
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case fn := <-cancelC:
+					fn()
+				}
+			}
+		}()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			waitCtx, waitCancel := context.WithCancel(ctx)
+			setC <- struct{}{}
+			cancelC <- waitCancel
+			cell.Wait(waitCtx)
+			waitCancel()
+		}
+	})
+}
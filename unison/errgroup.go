@@ -0,0 +1,101 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package unison
+
+import (
+	"context"
+	"sync"
+
+	"github.com/elastic/go-concert/ctxtool"
+)
+
+// ErrGroup is a collection of go-routines working on subtasks of a common
+// task, similar to golang.org/x/sync/errgroup. The group context is cancelled
+// the moment a go-routine returns an error, and Wait reports the first error
+// encountered.
+//
+// Unlike MultiErrGroup only the first error is reported, and unlike TaskGroup
+// there is no configurable OnQuit behavior. A go-routine returning
+// context.Canceled is not considered to have failed.
+//
+// The zero value of ErrGroup is a valid group.
+type ErrGroup struct {
+	wg SafeWaitGroup
+
+	initOnce sync.Once
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	errOnce sync.Once
+	err     error
+}
+
+var _ Group = (*ErrGroup)(nil)
+
+// ErrGroupWithCancel creates an ErrGroup, whose context is cancelled when
+// the parent context signals shutdown, a go-routine fails, or Wait returns.
+func ErrGroupWithCancel(parent Canceler) *ErrGroup {
+	g := &ErrGroup{}
+	g.init(parent)
+	return g
+}
+
+func (g *ErrGroup) init(parent Canceler) {
+	g.initOnce.Do(func() {
+		g.ctx, g.cancel = context.WithCancel(ctxtool.FromCanceller(parent))
+	})
+}
+
+// Context returns the group context passed to all go-routines. The context is
+// cancelled once a go-routine has failed, or Wait returns.
+func (g *ErrGroup) Context() context.Context {
+	g.init(context.Background())
+	return g.ctx
+}
+
+// Go starts a new go-routine, passing the group context to fn. Go returns
+// ErrGroupClosed if Wait has been called or a go-routine has already failed.
+func (g *ErrGroup) Go(fn func(context.Context) error) error {
+	g.init(context.Background())
+
+	if err := g.wg.Add(1); err != nil {
+		return err
+	}
+
+	go func() {
+		defer g.wg.Done()
+		if err := fn(g.ctx); err != nil && err != context.Canceled {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.wg.Close()
+				g.cancel()
+			})
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until all go-routines have returned, and returns the first error
+// encountered. Wait closes the group, such that no new go-routines can be
+// started.
+func (g *ErrGroup) Wait() error {
+	g.init(context.Background())
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,123 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package unison
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestErrGroup(t *testing.T) {
+	t.Run("wait returns nil if all go-routines succeed", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var grp ErrGroup
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, grp.Go(func(_ context.Context) error { return nil }))
+		}
+		assert.NoError(t, grp.Wait())
+	})
+
+	t.Run("wait returns first error", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		errFirst := errors.New("first")
+		errSecond := errors.New("second")
+
+		var grp ErrGroup
+		failed := make(chan struct{})
+		grp.Go(func(_ context.Context) error {
+			defer close(failed)
+			return errFirst
+		})
+		grp.Go(func(ctx context.Context) error {
+			<-failed
+			<-ctx.Done()
+			return errSecond
+		})
+
+		assert.Equal(t, errFirst, grp.Wait())
+	})
+
+	t.Run("failure cancels siblings", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var grp ErrGroup
+		siblingErr := make(chan error, 1)
+		grp.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+			siblingErr <- ctx.Err()
+			return ctx.Err()
+		})
+		grp.Go(func(_ context.Context) error { return errors.New("oops") })
+
+		assert.Error(t, grp.Wait())
+		assert.Equal(t, context.Canceled, <-siblingErr)
+	})
+
+	t.Run("cancel is not an error", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var grp ErrGroup
+		grp.Go(func(_ context.Context) error { return context.Canceled })
+		grp.Go(func(ctx context.Context) error {
+			assert.NoError(t, ctx.Err())
+			return nil
+		})
+		assert.NoError(t, grp.Wait())
+	})
+
+	t.Run("go fails after failure", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var grp ErrGroup
+		grp.Go(func(_ context.Context) error { return errors.New("oops") })
+		<-grp.Context().Done()
+
+		err := grp.Go(func(_ context.Context) error { return nil })
+		assert.Equal(t, ErrGroupClosed, err)
+		assert.Error(t, grp.Wait())
+	})
+
+	t.Run("go fails after wait", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var grp ErrGroup
+		assert.NoError(t, grp.Wait())
+		assert.Equal(t, ErrGroupClosed, grp.Go(func(_ context.Context) error { return nil }))
+		assert.Error(t, grp.Context().Err())
+	})
+
+	t.Run("parent cancel cancels go-routines", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		parent, cancel := context.WithCancel(context.Background())
+		grp := ErrGroupWithCancel(parent)
+		grp.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		cancel()
+		assert.NoError(t, grp.Wait())
+	})
+}
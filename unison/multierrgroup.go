@@ -19,6 +19,8 @@ package unison
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"sync"
 )
 
@@ -28,26 +30,116 @@ import (
 //
 // The zero value of MultiErrGroup is a valid group.
 type MultiErrGroup struct {
+	// Ignore configures errors that should not be collected. An error is
+	// dropped if Ignore returns true. If not set, context.Canceled is ignored,
+	// unless IncludeCanceled is set.
+	// Ignore must not be set after the first go-routine has been spawned.
+	Ignore func(error) bool
+
+	// IncludeCanceled configures the group to also collect context.Canceled
+	// errors. context.DeadlineExceeded is always collected. IncludeCanceled
+	// has no effect if Ignore is set.
+	IncludeCanceled bool
+
+	// Limit configures the maximum number of concurrently running go-routines.
+	// If the limit is reached, Go blocks until another go-routine did return.
+	// Limit <= 0 disables the limit. Limit must not be set after the first
+	// go-routine has been spawned.
+	//
+	// Calling Go from within a go-routine owned by the same group can
+	// deadlock, if the limit is reached.
+	Limit int
+
 	mu   sync.Mutex
 	errs []error
 	wg   sync.WaitGroup
+
+	// start sequence of the go-routines that did report errs. Used by WaitErr
+	// to report errors in a stable order.
+	started int
+	errSeq  []int
+
+	limitOnce sync.Once
+	limit     chan struct{}
 }
 
 // Go starts a new go-routine, collecting errors encounted into the
-// MultiErrGroup.
+// MultiErrGroup. If Limit is configured, Go blocks until the number of active
+// go-routines is below the limit.
 func (g *MultiErrGroup) Go(fn func() error) {
+	g.start(nil, fn)
+}
+
+// GoCtx starts a new go-routine, passing ctx to fn. Errors encountered are
+// collected into the MultiErrGroup like with Go. If Limit is configured, GoCtx
+// stops waiting for the number of active go-routines to drop below the limit
+// once ctx is cancelled. In that case fn is not run, and the context's error
+// is collected instead.
+func (g *MultiErrGroup) GoCtx(ctx context.Context, fn func(context.Context) error) {
+	g.start(ctx, func() error {
+		return fn(ctx)
+	})
+}
+
+func (g *MultiErrGroup) start(ctx context.Context, fn func() error) {
+	g.mu.Lock()
+	seq := g.started
+	g.started++
+	g.mu.Unlock()
+
+	limit := g.limiter()
+	if limit != nil {
+		if err := acquireSlot(ctx, limit); err != nil {
+			g.record(seq, err)
+			return
+		}
+	}
+
 	g.wg.Add(1)
 	go func() {
 		defer g.wg.Done()
-		err := fn()
-		if err != nil && err != context.Canceled {
-			g.mu.Lock()
-			defer g.mu.Unlock()
-			g.errs = append(g.errs, err)
+		if limit != nil {
+			defer func() { <-limit }()
+		}
+		if err := fn(); err != nil {
+			g.record(seq, err)
 		}
 	}()
 }
 
+// acquireSlot blocks until a slot in limit is available. If ctx is not nil,
+// acquireSlot returns the context's error once ctx is cancelled.
+func acquireSlot(ctx context.Context, limit chan struct{}) error {
+	if ctx == nil {
+		limit <- struct{}{}
+		return nil
+	}
+
+	// check for cancellation first, as select picks a random case if the
+	// limit and the context are ready at the same time.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case limit <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *MultiErrGroup) record(seq int, err error) {
+	if g.ignore(err) {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.errs = append(g.errs, err)
+	g.errSeq = append(g.errSeq, seq)
+}
+
 // Wait waits until all go-routines have been stopped and returns all errors
 // encountered.
 func (g *MultiErrGroup) Wait() []error {
@@ -56,3 +148,51 @@ func (g *MultiErrGroup) Wait() []error {
 	defer g.mu.Unlock()
 	return g.errs
 }
+
+// WaitErr waits until all go-routines have been stopped and returns the
+// errors encountered as a single error. WaitErr returns nil if no error was
+// encountered, and the error as is if only one go-routine did fail.
+// Otherwise the errors are combined using errors.Join, ordered by the
+// sequence the go-routines have been started in.
+func (g *MultiErrGroup) WaitErr() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch len(g.errs) {
+	case 0:
+		return nil
+	case 1:
+		return g.errs[0]
+	}
+
+	order := make([]int, len(g.errs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return g.errSeq[order[i]] < g.errSeq[order[j]]
+	})
+
+	errs := make([]error, len(order))
+	for i, idx := range order {
+		errs[i] = g.errs[idx]
+	}
+	return errors.Join(errs...)
+}
+
+func (g *MultiErrGroup) limiter() chan struct{} {
+	g.limitOnce.Do(func() {
+		if g.Limit > 0 {
+			g.limit = make(chan struct{}, g.Limit)
+		}
+	})
+	return g.limit
+}
+
+func (g *MultiErrGroup) ignore(err error) bool {
+	if g.Ignore != nil {
+		return g.Ignore(err)
+	}
+	return !g.IncludeCanceled && err == context.Canceled
+}
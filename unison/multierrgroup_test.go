@@ -18,8 +18,13 @@
 package unison
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -42,4 +47,149 @@ func TestMultiErrGroup(t *testing.T) {
 		grp.Go(func() error { return errors.New("2") })
 		assert.Equal(t, 2, len(grp.Wait()))
 	})
+
+	t.Run("GoCtx passes context", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), "key", "value")
+
+		var grp MultiErrGroup
+		grp.GoCtx(ctx, func(ctx context.Context) error {
+			return fmt.Errorf("value: %v", ctx.Value("key"))
+		})
+		grp.GoCtx(ctx, func(ctx context.Context) error { return nil })
+
+		errs := grp.Wait()
+		assert.Equal(t, 1, len(errs))
+		assert.Equal(t, "value: value", errs[0].Error())
+	})
+
+	t.Run("concurrency never exceeds limit", func(t *testing.T) {
+		const limit = 3
+		const tasks = 20
+
+		var active, peak atomic.Int64
+		grp := MultiErrGroup{Limit: limit}
+		for i := 0; i < tasks; i++ {
+			grp.Go(func() error {
+				n := active.Add(1)
+				defer active.Add(-1)
+				for {
+					old := peak.Load()
+					if n <= old || peak.CompareAndSwap(old, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return errors.New("oops")
+			})
+		}
+
+		assert.Equal(t, tasks, len(grp.Wait()))
+		assert.LessOrEqual(t, peak.Load(), int64(limit))
+	})
+
+	t.Run("GoCtx stops waiting for limit on cancel", func(t *testing.T) {
+		release := make(chan struct{})
+		grp := MultiErrGroup{Limit: 1}
+		grp.Go(func() error {
+			<-release
+			return nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		var ran atomic.Bool
+		grp.GoCtx(ctx, func(_ context.Context) error {
+			ran.Store(true)
+			return nil
+		})
+
+		close(release)
+		assert.Equal(t, []error{context.DeadlineExceeded}, grp.Wait())
+		assert.False(t, ran.Load())
+	})
+
+	t.Run("ignores context.Canceled by default", func(t *testing.T) {
+		var grp MultiErrGroup
+		grp.Go(func() error { return context.Canceled })
+		grp.Go(func() error { return io.EOF })
+		assert.Equal(t, []error{io.EOF}, grp.Wait())
+	})
+
+	t.Run("include canceled", func(t *testing.T) {
+		grp := MultiErrGroup{IncludeCanceled: true}
+		grp.Go(func() error { return context.Canceled })
+		grp.Go(func() error { return nil })
+		assert.Equal(t, []error{context.Canceled}, grp.Wait())
+	})
+
+	t.Run("deadline exceeded is collected by default", func(t *testing.T) {
+		var grp MultiErrGroup
+		grp.Go(func() error { return context.DeadlineExceeded })
+		assert.Equal(t, []error{context.DeadlineExceeded}, grp.Wait())
+	})
+
+	t.Run("custom ignore", func(t *testing.T) {
+		grp := MultiErrGroup{
+			Ignore: func(err error) bool {
+				return errors.Is(err, io.EOF) || errors.Is(err, context.Canceled)
+			},
+		}
+		errTest := errors.New("test")
+		grp.Go(func() error { return io.EOF })
+		grp.Go(func() error { return context.Canceled })
+		grp.Go(func() error { return errTest })
+		assert.Equal(t, []error{errTest}, grp.Wait())
+	})
+
+	t.Run("custom ignore can collect context.Canceled", func(t *testing.T) {
+		grp := MultiErrGroup{
+			Ignore: func(err error) bool { return false },
+		}
+		grp.Go(func() error { return context.Canceled })
+		assert.Equal(t, []error{context.Canceled}, grp.Wait())
+	})
+}
+
+func TestMultiErrGroup_WaitErr(t *testing.T) {
+	t.Run("returns nil if no go-routine was started", func(t *testing.T) {
+		var grp MultiErrGroup
+		assert.NoError(t, grp.WaitErr())
+	})
+
+	t.Run("returns nil if all go-routines succeed", func(t *testing.T) {
+		var grp MultiErrGroup
+		grp.Go(func() error { return nil })
+		grp.Go(func() error { return context.Canceled })
+		assert.NoError(t, grp.WaitErr())
+	})
+
+	t.Run("returns single error as is", func(t *testing.T) {
+		errTest := errors.New("test")
+		var grp MultiErrGroup
+		grp.Go(func() error { return nil })
+		grp.Go(func() error { return errTest })
+		assert.Equal(t, errTest, grp.WaitErr())
+	})
+
+	t.Run("joins errors in start order", func(t *testing.T) {
+		errs := []error{errors.New("err0"), errors.New("err1"), errors.New("err2")}
+
+		var grp MultiErrGroup
+		for i, err := range errs {
+			// go-routines started later return first
+			delay := time.Duration(len(errs)-i) * 10 * time.Millisecond
+			err := err
+			grp.Go(func() error {
+				time.Sleep(delay)
+				return err
+			})
+		}
+
+		err := grp.WaitErr()
+		for _, e := range errs {
+			assert.True(t, errors.Is(err, e))
+		}
+		assert.Equal(t, "err0\nerr1\nerr2", err.Error())
+	})
 }
@@ -104,6 +104,52 @@ func (c Mutex) LockContext(context doneContext) error {
 	}
 }
 
+// LockDeadline tries to lock the mutex, until either the configured duration
+// has passed or the context has been cancelled. LockDeadline returns true on
+// success. A failed lock attempt due to timeout returns false and nil. If the
+// context is cancelled first, false and the error returned by context.Err are
+// returned.
+//
+// If duration is 0, then the call behaves like TryLock, unless the context
+// has already been cancelled. If duration is <0, then the call behaves like
+// LockContext.
+//
+// The zero value of Mutex will never succeed.
+func (c Mutex) LockDeadline(context doneContext, duration time.Duration) (bool, error) {
+	select {
+	case <-context.Done():
+		return false, context.Err()
+	default:
+	}
+
+	switch {
+	case duration == 0:
+		return c.TryLock(), nil
+	case duration < 0:
+		if err := c.LockContext(context); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-c.ch:
+		return true, nil
+	case <-context.Done():
+		return false, context.Err()
+	case <-timer.C:
+		select {
+		case <-c.ch: // still lock, if timer and lock occured at the same time
+			return true, nil
+		default:
+			return false, nil
+		}
+	}
+}
+
 // TryLock attempts to lock the mutex. If the mutex has been already locked
 // false is returned.
 func (c Mutex) TryLock() bool {
@@ -85,6 +85,36 @@ func testInitializedMutex(t *testing.T) {
 		m := MakeMutex()
 		assert.Equal(t, context.Canceled, m.LockContext(ctx))
 	})
+
+	t.Run("lock unlocked with deadline succeeds", func(t *testing.T) {
+		for _, d := range []time.Duration{-1, 0, 10 * time.Minute} {
+			m := MakeMutex()
+			ok, err := m.LockDeadline(context.Background(), d)
+			assert.NoError(t, err)
+			assert.Equal(t, true, ok, "duration %v", d)
+		}
+	})
+
+	t.Run("lock with deadline succeeds once unlocked", func(t *testing.T) {
+		m := lockedMutex()
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			m.Unlock()
+		}()
+
+		ok, err := m.LockDeadline(context.Background(), 10*time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, true, ok)
+	})
+
+	t.Run("locking unlocked mutex with deadline and cancelled context fails", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		m := MakeMutex()
+		ok, err := m.LockDeadline(ctx, 10*time.Minute)
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, false, ok)
+	})
 }
 
 func testLockedFails(t *testing.T, create func() Mutex) {
@@ -116,6 +146,38 @@ func testLockedFails(t *testing.T, create func() Mutex) {
 		cancel()
 		assert.Equal(t, context.Canceled, m.LockContext(ctx))
 	})
+
+	t.Run("lock with deadline times out", func(t *testing.T) {
+		m := create()
+		ok, err := m.LockDeadline(context.Background(), 10*time.Millisecond)
+		assert.NoError(t, err)
+		assert.Equal(t, false, ok)
+	})
+
+	t.Run("lock with deadline 0 fails", func(t *testing.T) {
+		m := create()
+		ok, err := m.LockDeadline(context.Background(), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, false, ok)
+	})
+
+	t.Run("lock with deadline and context canceling", func(t *testing.T) {
+		m := create()
+		ctx, cancel := context.WithCancel(context.Background())
+		go cancel()
+		ok, err := m.LockDeadline(ctx, 10*time.Minute)
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, false, ok)
+	})
+
+	t.Run("lock with no deadline and context canceling", func(t *testing.T) {
+		m := create()
+		ctx, cancel := context.WithCancel(context.Background())
+		go cancel()
+		ok, err := m.LockDeadline(ctx, -1)
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, false, ok)
+	})
 }
 
 func testUnlockedFails(t *testing.T, create func() Mutex) {
@@ -0,0 +1,110 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package unison
+
+import (
+	"context"
+	"sync"
+)
+
+// Pool runs submitted functions with a bounded number of concurrent workers.
+// Submit blocks if all workers are busy, providing backpressure to the
+// producer. Errors returned by the functions are collected and returned by
+// Close.
+//
+// Example:
+//
+//	pool := unison.NewPool(4)
+//	for _, item := range items {
+//		item := item
+//		if err := pool.Submit(ctx, func(ctx context.Context) error {
+//			return process(ctx, item)
+//		}); err != nil {
+//			break
+//		}
+//	}
+//	errs := pool.Close()
+type Pool struct {
+	tg  TaskGroup
+	sem chan struct{}
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewPool creates a new Pool, that runs up to workers functions concurrently.
+// NewPool panics if workers is not greater than 0.
+func NewPool(workers int) *Pool {
+	if workers <= 0 {
+		panic("unison.NewPool: workers must be greater than 0")
+	}
+
+	p := &Pool{
+		sem:  make(chan struct{}, workers),
+		done: make(chan struct{}),
+	}
+	p.tg.OnQuit = ContinueOnErrors
+	p.tg.MaxErrors = -1
+	return p
+}
+
+// Submit runs fn in the pool. Submit blocks until a worker is available, or
+// until ctx is cancelled. If ctx is cancelled before fn could be started,
+// ctx.Err() is returned. Submit returns ErrGroupClosed if the pool has been
+// closed.
+//
+// ctx is only used to interrupt the Submit call. fn receives the pool's
+// internal context, which is not cancelled by Close.
+func (p *Pool) Submit(ctx context.Context, fn func(context.Context) error) error {
+	// check for close and cancellation first, as select picks a random case
+	// if a worker is available and the pool is closed or ctx is cancelled.
+	select {
+	case <-p.done:
+		return ErrGroupClosed
+	default:
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.done:
+		return ErrGroupClosed
+	}
+
+	err := p.tg.Go(func(ctx context.Context) error {
+		defer func() { <-p.sem }()
+		return fn(ctx)
+	})
+	if err != nil {
+		<-p.sem
+	}
+	return err
+}
+
+// Close stops accepting new functions and waits for all submitted functions
+// to return. Pending Submit calls fail with ErrGroupClosed. Close returns all
+// errors returned by the submitted functions.
+func (p *Pool) Close() []error {
+	p.closeOnce.Do(func() { close(p.done) })
+	p.tg.Wait()
+	return p.tg.Errors()
+}
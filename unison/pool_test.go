@@ -0,0 +1,189 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package unison
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("run submitted functions", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var count atomic.Int64
+		pool := NewPool(2)
+		for i := 0; i < 10; i++ {
+			err := pool.Submit(context.Background(), func(_ context.Context) error {
+				count.Add(1)
+				return nil
+			})
+			assert.NoError(t, err)
+		}
+
+		assert.Empty(t, pool.Close())
+		assert.Equal(t, int64(10), count.Load())
+	})
+
+	t.Run("limits concurrency", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		const workers = 3
+		var active, maxActive atomic.Int64
+		pool := NewPool(workers)
+		for i := 0; i < 20; i++ {
+			pool.Submit(context.Background(), func(_ context.Context) error {
+				n := active.Add(1)
+				defer active.Add(-1)
+				for {
+					max := maxActive.Load()
+					if n <= max || maxActive.CompareAndSwap(max, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return nil
+			})
+		}
+
+		pool.Close()
+		assert.LessOrEqual(t, maxActive.Load(), int64(workers))
+	})
+
+	t.Run("submit blocks if all workers are busy", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		block := make(chan struct{})
+		pool := NewPool(2)
+		for i := 0; i < 2; i++ {
+			pool.Submit(context.Background(), func(_ context.Context) error {
+				<-block
+				return nil
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := pool.Submit(ctx, func(_ context.Context) error { return nil })
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		close(block)
+		assert.NoError(t, pool.Submit(context.Background(), func(_ context.Context) error { return nil }))
+		assert.Empty(t, pool.Close())
+	})
+
+	t.Run("close collects all errors", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		pool := NewPool(2)
+		for i := 0; i < 15; i++ {
+			i := i
+			pool.Submit(context.Background(), func(_ context.Context) error {
+				if i%3 == 0 {
+					return fmt.Errorf("failure %v", i)
+				}
+				return nil
+			})
+		}
+
+		errs := pool.Close()
+		assert.Len(t, errs, 5)
+	})
+
+	t.Run("errors do not stop other functions", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var count atomic.Int64
+		pool := NewPool(1)
+		pool.Submit(context.Background(), func(_ context.Context) error {
+			return errors.New("oops")
+		})
+		err := pool.Submit(context.Background(), func(ctx context.Context) error {
+			assert.NoError(t, ctx.Err())
+			count.Add(1)
+			return nil
+		})
+		assert.NoError(t, err)
+
+		assert.Len(t, pool.Close(), 1)
+		assert.Equal(t, int64(1), count.Load())
+	})
+
+	t.Run("submit after close fails", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		pool := NewPool(1)
+		pool.Close()
+		err := pool.Submit(context.Background(), func(_ context.Context) error { return nil })
+		assert.Equal(t, ErrGroupClosed, err)
+	})
+
+	t.Run("close releases blocked submit", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		block := make(chan struct{})
+		pool := NewPool(1)
+		pool.Submit(context.Background(), func(_ context.Context) error {
+			<-block
+			return nil
+		})
+
+		submitted := make(chan error)
+		go func() {
+			submitted <- pool.Submit(context.Background(), func(_ context.Context) error { return nil })
+		}()
+
+		closed := make(chan []error)
+		go func() { closed <- pool.Close() }()
+
+		assert.Equal(t, ErrGroupClosed, <-submitted)
+		close(block)
+		assert.Empty(t, <-closed)
+	})
+
+	t.Run("cancelled context never starts function", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var count atomic.Int64
+		pool := NewPool(1)
+		for i := 0; i < 100; i++ {
+			err := pool.Submit(ctx, func(_ context.Context) error {
+				count.Add(1)
+				return nil
+			})
+			assert.Equal(t, context.Canceled, err)
+		}
+
+		assert.Empty(t, pool.Close())
+		assert.Equal(t, int64(0), count.Load())
+	})
+
+	t.Run("panics on invalid number of workers", func(t *testing.T) {
+		expectPanic(t, func() { NewPool(0) })
+	})
+}
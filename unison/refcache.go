@@ -0,0 +1,98 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package unison
+
+import (
+	"sync"
+
+	concert "github.com/elastic/go-concert"
+)
+
+// RefCache shares reference counted values by key. The first call to Acquire
+// for a key creates the value. Subsequent calls return the same value, until
+// all holders have released the value. The value is cleaned up once the last
+// holder did release it. Acquiring a key again afterwards creates a new value.
+//
+// RefCache can be used to share resources like connections between multiple
+// users.
+//
+// The zero value of RefCache is valid, but a value of type RefCache can not be
+// copied.
+type RefCache[K comparable, V any] struct {
+	mu    sync.Mutex
+	table map[K]*refCacheEntry[V]
+}
+
+type refCacheEntry[V any] struct {
+	ref     concert.RefCount
+	value   V
+	cleanup func()
+}
+
+// Acquire returns the value for key and increases its reference count. If no
+// active value is known for key, create is called to create the value and its
+// cleanup function. The cleanup function can be nil.
+//
+// The returned release function must be called once the value is not needed
+// anymore. Calling release multiple times is safe.
+// The cleanup function is run by the last call to release. If the value is
+// acquired again while the old value is cleaned up, a new value will be
+// created. Cleanup of the old value and creation of the new value might run
+// concurrently.
+//
+// create is called while the cache is locked, and must not call back into
+// the cache.
+func (c *RefCache[K, V]) Acquire(key K, create func() (V, func())) (V, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.table[key]
+	if !exists || !entry.ref.TryRetain() {
+		// No entry or the entry is being released concurrently. We can not
+		// revive an entry that has been released, so let's replace it.
+		value, cleanup := create()
+		entry = &refCacheEntry[V]{value: value, cleanup: cleanup}
+		if c.table == nil {
+			c.table = map[K]*refCacheEntry[V]{}
+		}
+		c.table[key] = entry
+	}
+
+	var once sync.Once
+	return entry.value, func() {
+		once.Do(func() { c.release(key, entry) })
+	}
+}
+
+func (c *RefCache[K, V]) release(key K, entry *refCacheEntry[V]) {
+	if !entry.ref.Release() {
+		return
+	}
+
+	// The entry might have been replaced already, in case a concurrent Acquire
+	// did observe the free reference count.
+	c.mu.Lock()
+	if c.table[key] == entry {
+		delete(c.table, key)
+	}
+	c.mu.Unlock()
+
+	if entry.cleanup != nil {
+		entry.cleanup()
+	}
+}
@@ -0,0 +1,130 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package unison
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefCache(t *testing.T) {
+	type resource struct {
+		id     int
+		closed atomic.Bool
+	}
+
+	type counters struct {
+		created, cleaned atomic.Int64
+	}
+
+	creator := func(c *counters) func() (*resource, func()) {
+		return func() (*resource, func()) {
+			r := &resource{id: int(c.created.Add(1))}
+			return r, func() {
+				r.closed.Store(true)
+				c.cleaned.Add(1)
+			}
+		}
+	}
+
+	t.Run("value is shared", func(t *testing.T) {
+		var c counters
+		var cache RefCache[string, *resource]
+
+		r1, release1 := cache.Acquire("key", creator(&c))
+		r2, release2 := cache.Acquire("key", creator(&c))
+		assert.Same(t, r1, r2)
+		assert.Equal(t, int64(1), c.created.Load())
+
+		release1()
+		assert.Equal(t, int64(0), c.cleaned.Load())
+		release2()
+		assert.Equal(t, int64(1), c.cleaned.Load())
+		assert.True(t, r1.closed.Load())
+	})
+
+	t.Run("keys are independent", func(t *testing.T) {
+		var c counters
+		var cache RefCache[string, *resource]
+
+		r1, release1 := cache.Acquire("a", creator(&c))
+		r2, release2 := cache.Acquire("b", creator(&c))
+		assert.True(t, r1 != r2)
+
+		release1()
+		assert.True(t, r1.closed.Load())
+		assert.False(t, r2.closed.Load())
+		release2()
+	})
+
+	t.Run("release is idempotent", func(t *testing.T) {
+		var c counters
+		var cache RefCache[string, *resource]
+
+		_, release1 := cache.Acquire("key", creator(&c))
+		_, release2 := cache.Acquire("key", creator(&c))
+		release1()
+		release1()
+		assert.Equal(t, int64(0), c.cleaned.Load())
+		release2()
+		assert.Equal(t, int64(1), c.cleaned.Load())
+	})
+
+	t.Run("acquire after release creates new value", func(t *testing.T) {
+		var c counters
+		var cache RefCache[string, *resource]
+
+		r1, release := cache.Acquire("key", creator(&c))
+		release()
+
+		r2, release := cache.Acquire("key", creator(&c))
+		defer release()
+		assert.True(t, r1 != r2)
+		assert.False(t, r2.closed.Load())
+		assert.Equal(t, int64(2), c.created.Load())
+	})
+
+	t.Run("concurrent acquire and release", func(t *testing.T) {
+		const workers = 8
+		const iterations = 1000
+
+		var c counters
+		var cache RefCache[string, *resource]
+
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < iterations; i++ {
+					r, release := cache.Acquire("key", creator(&c))
+					if r.closed.Load() {
+						t.Error("acquired resource that has been cleaned up")
+					}
+					release()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, c.created.Load(), c.cleaned.Load())
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 
 	"github.com/elastic/go-concert/ctxtool"
 )
@@ -30,6 +31,7 @@ import (
 type SafeWaitGroup struct {
 	mu     sync.RWMutex
 	wg     sync.WaitGroup
+	count  atomic.Int64
 	cancel context.CancelFunc
 	closed bool
 }
@@ -56,6 +58,7 @@ func SafeWaitGroupWithCancel(parent Canceler) *SafeWaitGroup {
 // go-routines should be started.
 func (s *SafeWaitGroup) Add(n int) error {
 	if n < 0 {
+		s.count.Add(int64(n))
 		s.wg.Add(n)
 		return nil
 	}
@@ -66,15 +69,24 @@ func (s *SafeWaitGroup) Add(n int) error {
 		return ErrGroupClosed
 	}
 
+	s.count.Add(int64(n))
 	s.wg.Add(n)
 	return nil
 }
 
 // Done decrements the WaitGroup counter.
 func (s *SafeWaitGroup) Done() {
+	s.count.Add(-1)
 	s.wg.Done()
 }
 
+// Count returns the current value of the WaitGroup counter, which is the
+// number of outstanding go-routines. The value is only a snapshot, and might
+// be outdated by the time it is returned.
+func (s *SafeWaitGroup) Count() int {
+	return int(s.count.Load())
+}
+
 // Close marks the wait group as closed. All calls to Add will fail with ErrGroupClosed after
 // close has been called. Close does not wait until the WaitGroup counter has
 // reached zero, but will return immediately. Use Wait to wait for the counter to become 0.
@@ -104,3 +116,26 @@ func (s *SafeWaitGroup) Wait() {
 	s.Close()
 	s.wg.Wait()
 }
+
+// WaitContext closes the WaitGroup and blocks until the WaitGroup counter is
+// zero, or ctx is cancelled. WaitContext returns ctx.Err() if ctx was
+// cancelled before the counter did reach zero.
+//
+// WaitContext starts a helper go-routine, which returns once the counter
+// reaches zero, even if WaitContext did return early.
+func (s *SafeWaitGroup) WaitContext(ctx Canceler) error {
+	s.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.wg.Wait()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
 )
 
 func TestSafeWaitGroup(t *testing.T) {
@@ -108,6 +109,46 @@ func TestSafeWaitGroup(t *testing.T) {
 		wg.Wait() // will block if counter resource has not been released
 	})
 
+	t.Run("wait context returns on zero counter", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var wg SafeWaitGroup
+		require.NoError(t, wg.Add(1))
+		go wg.Done()
+		require.NoError(t, wg.WaitContext(context.Background()))
+		require.Equal(t, ErrGroupClosed, wg.Add(1))
+	})
+
+	t.Run("wait context returns on cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		var wg SafeWaitGroup
+		require.NoError(t, wg.Add(1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.Equal(t, context.DeadlineExceeded, wg.WaitContext(ctx))
+		require.Equal(t, ErrGroupClosed, wg.Add(1))
+
+		// helper go-routine returns once the counter reaches zero
+		wg.Done()
+	})
+
+	t.Run("count outstanding", func(t *testing.T) {
+		var wg SafeWaitGroup
+		require.Equal(t, 0, wg.Count())
+		require.NoError(t, wg.Add(3))
+		require.Equal(t, 3, wg.Count())
+		wg.Done()
+		require.Equal(t, 2, wg.Count())
+		require.NoError(t, wg.Add(-2))
+		require.Equal(t, 0, wg.Count())
+
+		wg.Close()
+		require.Equal(t, ErrGroupClosed, wg.Add(1))
+		require.Equal(t, 0, wg.Count())
+	})
+
 	t.Run("with context", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.TODO())
 		wg := SafeWaitGroupWithCancel(ctx)
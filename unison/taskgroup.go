@@ -21,9 +21,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime/debug"
 	"sync"
+	"time"
 
 	"github.com/elastic/go-concert/ctxtool"
+	"github.com/elastic/go-concert/timed"
 )
 
 // Group interface, that can be used to start tasks. The tasks started will
@@ -42,6 +45,11 @@ type Canceler interface {
 	Err() error
 }
 
+// ErrStopGroup can be returned by a go-routine managed by a TaskGroup, in
+// order to signal the TaskGroup to shutdown. ErrStopGroup is not handled by
+// OnQuit and is not reported as error by Wait or Stop.
+var ErrStopGroup = errors.New("stop group")
+
 type closedGroup struct {
 	err error
 }
@@ -89,9 +97,34 @@ type TaskGroup struct {
 	// If MaxErrors is set to a value < 0, all errors will be recorded.
 	MaxErrors int
 
-	mu   sync.Mutex
-	errs []error
-	wg   SafeWaitGroup
+	// RestartBackoff configures the delay before a go-routine is restarted,
+	// after OnQuit did return TaskGroupStopActionRestart. The attempt counter
+	// starts with 1 and is incremented on consecutive restarts. The delay is
+	// interrupted if the group is stopped. If not set, the go-routine is
+	// restarted immediately.
+	RestartBackoff func(attempt int) time.Duration
+
+	// RestartResetAfter configures how long a go-routine must have been
+	// running before it returned, for the attempt counter passed to
+	// RestartBackoff to be reset. If 0, the counter is never reset.
+	RestartResetAfter time.Duration
+
+	// RecoverPanic configures the TaskGroup to recover panics in managed
+	// go-routines. A recovered panic is reported as *PanicError, and handled
+	// by OnQuit like any other error returned by the go-routine. By default
+	// panics are not recovered.
+	RecoverPanic bool
+
+	// Limit configures the maximum number of concurrently running go-routines.
+	// If the limit is reached, Go blocks until another go-routine did return.
+	// Limit <= 0 disables the limit. Limit must not be set after the first
+	// go-routine has been spawned.
+	Limit int
+
+	mu    sync.Mutex
+	errs  []error
+	wg    SafeWaitGroup
+	limit chan struct{}
 
 	initOnce sync.Once
 	closer   context.Context
@@ -118,6 +151,15 @@ const (
 	TaskGroupStopActionRestart
 )
 
+// PanicError reports a panic recovered by a TaskGroup.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the panicking go-routine.
+	Stack []byte
+}
+
 var _ Group = (*TaskGroup)(nil)
 
 // init initializes internal state the first time the group is actively used.
@@ -130,6 +172,9 @@ func (t *TaskGroup) init(parent Canceler) {
 		if t.MaxErrors == 0 {
 			t.MaxErrors = 10
 		}
+		if t.Limit > 0 {
+			t.limit = make(chan struct{}, t.Limit)
+		}
 	})
 }
 
@@ -150,21 +195,49 @@ func TaskGroupWithCancel(canceler Canceler) *TaskGroup {
 // Errors returned by the function are collected and finally returned on Stop.
 // If the group was stopped before calling Go, then Go will return the
 // ErrGroupClosed error.
+//
+// If Limit is configured, Go blocks until the number of active go-routines
+// is below the limit. Go returns ErrGroupClosed if the group is stopped while
+// Go is blocked.
 func (t *TaskGroup) Go(fn func(context.Context) error) error {
+	return t.GoNamed("", fn)
+}
+
+// GoNamed starts a new go-routine like Go. Errors returned by the function
+// are annotated with the task name before being collected. If name is empty,
+// GoNamed behaves like Go.
+func (t *TaskGroup) GoNamed(name string, fn func(context.Context) error) error {
 	t.init(context.Background())
 
 	if err := t.wg.Add(1); err != nil {
 		return err
 	}
 
+	if err := t.acquireLimit(); err != nil {
+		t.wg.Done()
+		return err
+	}
+
 	go func() {
 		defer t.wg.Done()
+		defer t.releaseLimit()
 
+		attempt := 0
 		for t.closer.Err() == nil {
-			err := fn(t.closer)
+			start := time.Now()
+			err := t.run(fn)
+			if errors.Is(err, ErrStopGroup) {
+				t.signalStop()
+				return
+			}
+
 			action, err := t.OnQuit(err)
 
 			if err != nil && err != context.Canceled {
+				if name != "" {
+					err = fmt.Errorf("task %q: %w", name, err)
+				}
+
 				t.mu.Lock()
 				t.errs = append(t.errs, err)
 				if t.MaxErrors > 0 && len(t.errs) > t.MaxErrors {
@@ -180,7 +253,15 @@ func (t *TaskGroup) Go(fn func(context.Context) error) error {
 				t.signalStop()
 				return
 			case TaskGroupStopActionRestart:
-				// continue with loop
+				if t.RestartResetAfter > 0 && time.Since(start) >= t.RestartResetAfter {
+					attempt = 0
+				}
+				attempt++
+				if t.RestartBackoff != nil {
+					// Wait fails if the group is stopped, in which case the loop
+					// condition will be false.
+					timed.Wait(t.closer, t.RestartBackoff(attempt))
+				}
 			}
 		}
 	}()
@@ -188,6 +269,52 @@ func (t *TaskGroup) Go(fn func(context.Context) error) error {
 	return nil
 }
 
+// run executes fn, converting a panic into a *PanicError if RecoverPanic is set.
+func (t *TaskGroup) run(fn func(context.Context) error) (err error) {
+	if !t.RecoverPanic {
+		return fn(t.closer)
+	}
+
+	// recover returns nil on panic(nil), so we track completion of fn in
+	// order to detect all panics.
+	completed := false
+	defer func() {
+		if v := recover(); !completed {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	err = fn(t.closer)
+	completed = true
+	return err
+}
+
+// acquireLimit blocks until a go-routine can be started without exceeding the
+// configured Limit.
+func (t *TaskGroup) acquireLimit() error {
+	if t.limit == nil {
+		return nil
+	}
+
+	// check for shutdown first, as select picks a random case if the limit
+	// and the shutdown signal are ready at the same time.
+	if t.closer.Err() != nil {
+		return ErrGroupClosed
+	}
+
+	select {
+	case t.limit <- struct{}{}:
+		return nil
+	case <-t.closer.Done():
+		return ErrGroupClosed
+	}
+}
+
+func (t *TaskGroup) releaseLimit() {
+	if t.limit != nil {
+		<-t.limit
+	}
+}
+
 // Context returns the task groups internal context.
 // The internal context will be cancelled if the groups parent context gets
 // cancelled, or Stop has been called.
@@ -198,21 +325,58 @@ func (t *TaskGroup) Context() context.Context {
 
 // Wait blocks until all owned child routines have been stopped.
 func (t *TaskGroup) Wait() error {
-	errs := t.waitErrors()
-	if len(errs) > 0 {
-		return fmt.Errorf("task failures: %w", errors.Join(errs...))
+	return joinTaskErrors(t.waitErrors())
+}
+
+// WaitContext blocks until all owned child routines have been stopped, or
+// until ctx is cancelled. WaitContext returns ctx.Err() if ctx was cancelled
+// before all go-routines did return.
+//
+// Like Wait, WaitContext closes the group, such that no new go-routines can be
+// started. Go-routines that did not return before ctx was cancelled continue
+// running in the background. Wait or WaitContext can be called again to wait
+// for them.
+func (t *TaskGroup) WaitContext(ctx Canceler) error {
+	if err := t.wg.WaitContext(ctx); err != nil {
+		return err
 	}
-	return nil
+	return joinTaskErrors(t.collectedErrors())
+}
+
+// Errors returns a copy of the errors collected so far. Errors does not wait
+// for the managed go-routines to return, and does not reset the collected
+// errors. The number of errors returned is limited by MaxErrors.
+func (t *TaskGroup) Errors() []error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.errs) == 0 {
+		return nil
+	}
+	errs := make([]error, len(t.errs))
+	copy(errs, t.errs)
+	return errs
 }
 
 func (t *TaskGroup) waitErrors() []error {
 	t.wg.Wait()
+	return t.collectedErrors()
+}
+
+func (t *TaskGroup) collectedErrors() []error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	return t.errs
 }
 
+func joinTaskErrors(errs []error) error {
+	if len(errs) > 0 {
+		return fmt.Errorf("task failures: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
 // Stop sends a shutdown signal to all tasks, and waits for them to finish.
 // It returns an error that contains all errors encountered.
 func (t *TaskGroup) Stop() error {
@@ -229,6 +393,10 @@ func (t *TaskGroup) signalStop() {
 	t.cancel()
 }
 
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", e.Value, e.Stack)
+}
+
 // ContinueOnErrors provides a TaskGroup.OnQuit handler, that will ignore
 // any errors. Other go-routines owned by the TaskGroup will continue to run.
 func ContinueOnErrors(err error) (TaskGroupStopAction, error) {
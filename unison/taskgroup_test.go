@@ -22,7 +22,9 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -117,6 +119,228 @@ func TestTaskGroup_MaxErrors(t *testing.T) {
 	require.Equal(t, want, got)
 }
 
+func TestTaskGroup_Limit(t *testing.T) {
+	t.Run("concurrency never exceeds limit", func(t *testing.T) {
+		const limit = 3
+		const tasks = 20
+
+		var active, peak atomic.Int64
+		tg := TaskGroup{Limit: limit}
+		for i := 0; i < tasks; i++ {
+			err := tg.Go(func(_ context.Context) error {
+				n := active.Add(1)
+				defer active.Add(-1)
+				for {
+					old := peak.Load()
+					if n <= old || peak.CompareAndSwap(old, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, tg.Wait())
+		require.LessOrEqual(t, peak.Load(), int64(limit))
+		require.Greater(t, peak.Load(), int64(0))
+	})
+
+	t.Run("blocked Go returns on stop", func(t *testing.T) {
+		tg := TaskGroup{Limit: 1}
+		wgStart := wgCount(1)
+		require.NoError(t, tg.Go(func(ctx context.Context) error {
+			wgStart.Done()
+			<-ctx.Done()
+			return nil
+		}))
+		wgStart.Wait()
+
+		errc := make(chan error, 1)
+		go func() {
+			errc <- tg.Go(func(_ context.Context) error {
+				t.Error("unexpected task start")
+				return nil
+			})
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, tg.Stop())
+		require.Equal(t, ErrGroupClosed, <-errc)
+	})
+}
+
+func TestTaskGroup_GoNamed(t *testing.T) {
+	t.Run("error is annotated with task name", func(t *testing.T) {
+		errTest := errors.New("oops")
+		tg := TaskGroup{OnQuit: ContinueOnErrors}
+		tg.GoNamed("worker", func(_ context.Context) error { return errTest })
+
+		errs := tg.waitErrors()
+		require.Len(t, errs, 1)
+		require.True(t, errors.Is(errs[0], errTest))
+		require.Equal(t, `task "worker": oops`, errs[0].Error())
+	})
+
+	t.Run("empty name does not annotate", func(t *testing.T) {
+		errTest := errors.New("oops")
+		tg := TaskGroup{OnQuit: ContinueOnErrors}
+		tg.GoNamed("", func(_ context.Context) error { return errTest })
+		require.Equal(t, []error{errTest}, tg.waitErrors())
+	})
+
+	t.Run("cancel is no error", func(t *testing.T) {
+		var tg TaskGroup
+		tg.GoNamed("worker", func(_ context.Context) error { return context.Canceled })
+		require.NoError(t, tg.Stop())
+	})
+}
+
+func TestTaskGroup_WaitContext(t *testing.T) {
+	t.Run("returns after workers finished", func(t *testing.T) {
+		tg := TaskGroup{OnQuit: ContinueOnErrors}
+		tg.Go(func(_ context.Context) error { return errors.New("oops") })
+
+		err := tg.WaitContext(context.Background())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "oops")
+	})
+
+	t.Run("returns context error if worker does not stop", func(t *testing.T) {
+		var tg TaskGroup
+		release := make(chan struct{})
+		wgStart := wgCount(1)
+		tg.Go(func(_ context.Context) error {
+			wgStart.Done()
+			<-release // ignore shutdown signal
+			return nil
+		})
+		wgStart.Wait()
+		tg.signalStop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.Equal(t, context.DeadlineExceeded, tg.WaitContext(ctx))
+
+		// group is closed, but worker can still finish
+		require.Equal(t, ErrGroupClosed, tg.Go(finishedGroupWorker))
+		close(release)
+		require.NoError(t, tg.Wait())
+	})
+}
+
+func TestTaskGroup_RecoverPanic(t *testing.T) {
+	t.Run("panic is reported as error", func(t *testing.T) {
+		tg := TaskGroup{RecoverPanic: true, OnQuit: ContinueOnErrors}
+		tg.Go(func(_ context.Context) error { panic("oops") })
+
+		errs := tg.waitErrors()
+		require.Len(t, errs, 1)
+
+		var panicErr *PanicError
+		require.True(t, errors.As(errs[0], &panicErr))
+		require.Equal(t, "oops", panicErr.Value)
+		require.Contains(t, string(panicErr.Stack), "TestTaskGroup_RecoverPanic")
+	})
+
+	t.Run("panic with nil value is reported as error", func(t *testing.T) {
+		tg := TaskGroup{RecoverPanic: true, OnQuit: ContinueOnErrors}
+		tg.Go(func(_ context.Context) error { panic(nil) })
+
+		errs := tg.waitErrors()
+		require.Len(t, errs, 1)
+
+		var panicErr *PanicError
+		require.True(t, errors.As(errs[0], &panicErr))
+	})
+
+	t.Run("panic is handled by OnQuit", func(t *testing.T) {
+		tg := TaskGroup{RecoverPanic: true, OnQuit: StopOnError}
+		testTaskGroupStopsIf(t, &tg, func(_ context.Context) error { panic("oops") })
+	})
+
+	t.Run("restart after panic", func(t *testing.T) {
+		var count int
+		tg := TaskGroup{RecoverPanic: true, OnQuit: RestartOnError}
+		tg.Go(func(_ context.Context) error {
+			count++
+			if count == 1 {
+				panic("oops")
+			}
+			return nil
+		})
+
+		require.Error(t, tg.Wait())
+		require.Equal(t, 2, count)
+	})
+}
+
+func TestTaskGroup_Errors(t *testing.T) {
+	t.Run("no errors", func(t *testing.T) {
+		var tg TaskGroup
+		require.Nil(t, tg.Errors())
+	})
+
+	t.Run("errors of running group", func(t *testing.T) {
+		tg := TaskGroup{OnQuit: ContinueOnErrors}
+		defer tg.Stop()
+
+		err1, err2 := errors.New("1"), errors.New("2")
+		for _, err := range []error{err1, err2} {
+			wg := wgCount(1)
+			tg.Go(func(_ context.Context) error {
+				defer wg.Done()
+				return err
+			})
+			wg.Wait()
+		}
+
+		// errors are recorded after the worker did return. Poll until both
+		// errors are available.
+		var errs []error
+		for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(time.Millisecond) {
+			if errs = tg.Errors(); len(errs) == 2 {
+				break
+			}
+		}
+		require.Equal(t, []error{err1, err2}, errs)
+		require.NoError(t, tg.Context().Err())
+
+		// Errors does not reset collected errors
+		require.Equal(t, errs, tg.Errors())
+	})
+}
+
+func TestTaskGroup_ErrStopGroup(t *testing.T) {
+	onQuits := map[string]TaskGroupQuitHandler{
+		"ContinueOnErrors": ContinueOnErrors,
+		"RestartOnError":   RestartOnError,
+		"StopOnError":      StopOnError,
+	}
+
+	for name, onQuit := range onQuits {
+		t.Run(name, func(t *testing.T) {
+			t.Run("stops group", func(t *testing.T) {
+				grp := TaskGroup{OnQuit: onQuit}
+				testTaskGroupStopsIf(t, &grp, func(_ context.Context) error {
+					return ErrStopGroup
+				})
+			})
+
+			t.Run("is not reported as error", func(t *testing.T) {
+				grp := TaskGroup{OnQuit: onQuit}
+				grp.Go(func(_ context.Context) error {
+					return fmt.Errorf("done: %w", ErrStopGroup)
+				})
+				require.NoError(t, grp.Wait())
+				require.Empty(t, grp.Errors())
+				require.Equal(t, ErrGroupClosed, grp.Go(finishedGroupWorker))
+			})
+		})
+	}
+}
+
 func TestTaskgroup_OnQuit_ContinueOnError(t *testing.T) {
 	onQuit := ContinueOnErrors
 
@@ -158,6 +382,89 @@ func TestTaskgroup_OnQuit_RestartOnError(t *testing.T) {
 
 }
 
+func TestTaskgroup_RestartBackoff(t *testing.T) {
+	t.Run("delay grows across consecutive failures", func(t *testing.T) {
+		const runs = 4
+		var attempts []int
+		var starts []time.Time
+
+		grp := TaskGroup{
+			OnQuit: RestartOnError,
+			RestartBackoff: func(attempt int) time.Duration {
+				attempts = append(attempts, attempt)
+				return time.Duration(attempt) * 20 * time.Millisecond
+			},
+		}
+		grp.Go(func(_ context.Context) error {
+			starts = append(starts, time.Now())
+			if len(starts) < runs {
+				return errors.New("oops")
+			}
+			return nil
+		})
+		grp.Wait()
+
+		require.Equal(t, []int{1, 2, 3}, attempts)
+		require.Len(t, starts, runs)
+		for i := 1; i < runs; i++ {
+			gap := starts[i].Sub(starts[i-1])
+			require.GreaterOrEqual(t, int64(gap), int64(time.Duration(i)*20*time.Millisecond))
+		}
+	})
+
+	t.Run("attempt counter is reset after long run", func(t *testing.T) {
+		var attempts []int
+		var count int
+
+		grp := TaskGroup{
+			OnQuit:            RestartOnError,
+			RestartResetAfter: 20 * time.Millisecond,
+			RestartBackoff: func(attempt int) time.Duration {
+				attempts = append(attempts, attempt)
+				return 0
+			},
+		}
+		grp.Go(func(_ context.Context) error {
+			count++
+			switch count {
+			case 3:
+				time.Sleep(30 * time.Millisecond)
+			case 5:
+				return nil
+			}
+			return errors.New("oops")
+		})
+		grp.Wait()
+
+		require.Equal(t, []int{1, 2, 1, 2}, attempts)
+	})
+
+	t.Run("stop interrupts backoff", func(t *testing.T) {
+		wgStart := wgCount(1)
+		grp := TaskGroup{
+			OnQuit:         RestartOnError,
+			RestartBackoff: func(_ int) time.Duration { return time.Hour },
+		}
+		grp.Go(func(_ context.Context) error {
+			wgStart.Done()
+			return errors.New("oops")
+		})
+		wgStart.Wait()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			grp.Stop()
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("stop did not interrupt the restart backoff")
+		}
+	})
+}
+
 func TestTaskgroup_OnQuit_StopAll(t *testing.T) {
 	onQuit := StopAll
 